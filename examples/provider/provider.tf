@@ -2,3 +2,15 @@ provider "forem" {
   api_key = var.api_key # optionally use FOREM_API_KEY env var
   host    = var.host    # optionally use FOREM_HOST env var
 }
+
+# Read the API key from a profile of ~/.config/forem/credentials
+provider "forem" {
+  alias   = "profile"
+  profile = "work" # optionally use FOREM_PROFILE env var
+}
+
+# Read the API key from the output of a command
+provider "forem" {
+  alias               = "command"
+  credentials_command = ["vault", "kv", "get", "-field=api_key", "secret/forem"]
+}
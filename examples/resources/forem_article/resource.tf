@@ -9,11 +9,11 @@ resource "forem_article" "example_basic" {
   body_markdown = "A simple single-line body that is very basic..."
 }
 
-# Article generated from a file
+# Article generated from a file, which only shows up in the plan as a checksum
 resource "forem_article" "example_file" {
-  title         = "Article from a file!"
-  body_markdown = file("${path.module}/files/example.md")
-  series        = local.series
+  title     = "Article from a file!"
+  body_file = "${path.module}/files/example.md"
+  series    = local.series
 
   tags = local.tags
 }
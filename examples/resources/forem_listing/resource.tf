@@ -21,9 +21,10 @@ resource "forem_listing" "example_full" {
   title               = "My first listing using TF Forem provider!"
   action              = "publish"
   category            = "cfp"
-  expires_at          = "09/06/2025"
   contact_via_connect = false
   location            = "Amsterdam"
+  # A listing expires within 30 days at most, so `expires_at` can only be set
+  # to a date in that window, e.g. `expires_at = "MM/DD/YYYY"`.
 
   body_markdown = <<-EOT
     This is the markdown for the listing
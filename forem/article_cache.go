@@ -0,0 +1,102 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	dev "github.com/karvounis/dev-client-go"
+)
+
+// readArticlesPerPage is the maximum page size allowed by the API.
+const readArticlesPerPage = 1000
+
+// articleCache holds the articles of the authenticated user for the duration
+// of a Terraform run. The Forem API has no endpoint that returns an
+// unpublished article by its ID, so instead of paginating through the user's
+// articles for every forem_article, a single sweep is shared by all of them.
+//
+// Articles that are created or updated by the provider are marked as stale
+// and trigger a new sweep the next time they are looked up.
+type articleCache struct {
+	mu       sync.Mutex
+	loaded   bool
+	articles map[string]dev.Article
+	stale    map[string]bool
+}
+
+func newArticleCache() *articleCache {
+	return &articleCache{
+		articles: map[string]dev.Article{},
+		stale:    map[string]bool{},
+	}
+}
+
+// Get returns the article of the authenticated user with the given ID. The
+// second return value is false if the user has no such article.
+func (c *articleCache) Get(ctx context.Context, client *apiClient, id string) (dev.Article, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded || c.stale[id] {
+		if err := c.load(ctx, client); err != nil {
+			return dev.Article{}, false, err
+		}
+	}
+	article, ok := c.articles[id]
+	return article, ok, nil
+}
+
+// GetByPath returns the article of the authenticated user with the given path,
+// i.e. `/username/slug`. The second return value is false if the user has no
+// such article.
+func (c *articleCache) GetByPath(ctx context.Context, client *apiClient, path string) (dev.Article, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded || len(c.stale) > 0 {
+		if err := c.load(ctx, client); err != nil {
+			return dev.Article{}, false, err
+		}
+	}
+	for _, article := range c.articles {
+		if strings.EqualFold(article.Path, path) {
+			return article, true, nil
+		}
+	}
+	return dev.Article{}, false, nil
+}
+
+// Invalidate marks the article with the given ID as stale.
+func (c *articleCache) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stale[id] = true
+}
+
+func (c *articleCache) load(ctx context.Context, client *apiClient) error {
+	articles := map[string]dev.Article{}
+	for page := int32(1); ; page++ {
+		tflog.Debug(ctx, fmt.Sprintf("Loading user articles with page: %d and perPage: %d", page, readArticlesPerPage))
+		resp, err := client.GetUserArticles(dev.ArticleQueryParams{Page: page, PerPage: readArticlesPerPage})
+		if err != nil {
+			return err
+		}
+		for _, v := range resp {
+			articles[strconv.Itoa(int(v.ID))] = v
+		}
+		if len(resp) < readArticlesPerPage {
+			break
+		}
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Loaded %d user articles", len(articles)))
+
+	c.articles = articles
+	c.stale = map[string]bool{}
+	c.loaded = true
+	return nil
+}
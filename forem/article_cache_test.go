@@ -0,0 +1,66 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestArticleCache_sharesSweepBetweenLookups(t *testing.T) {
+	var calls int32
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if got := r.URL.Query().Get("per_page"); got != fmt.Sprint(readArticlesPerPage) {
+			t.Errorf("expected per_page %d, got %s", readArticlesPerPage, got)
+		}
+		fmt.Fprint(w, `[{"id":1,"title":"first"},{"id":2,"title":"second"}]`)
+	}, 0)
+
+	ctx := context.Background()
+	for _, id := range []string{"1", "2", "3"} {
+		if _, _, err := c.articles.Get(ctx, c, id); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+
+	article, ok, _ := c.articles.Get(ctx, c, "2")
+	if !ok || article.Title != "second" {
+		t.Errorf("expected article `second`, got %+v", article)
+	}
+	if _, ok, _ := c.articles.Get(ctx, c, "3"); ok {
+		t.Error("expected article 3 to be missing")
+	}
+}
+
+func TestArticleCache_reloadsStaleArticles(t *testing.T) {
+	var calls int32
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			fmt.Fprint(w, `[{"id":1,"title":"first"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":1,"title":"first"},{"id":2,"title":"created"}]`)
+	}, 0)
+
+	ctx := context.Background()
+	if _, ok, _ := c.articles.Get(ctx, c, "1"); !ok {
+		t.Fatal("expected article 1 to be found")
+	}
+
+	c.articles.Invalidate("2")
+	article, ok, err := c.articles.Get(ctx, c, "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ok || article.Title != "created" {
+		t.Errorf("expected article `created`, got %+v", article)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,170 @@
+package forem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	dev "github.com/karvounis/dev-client-go"
+)
+
+// apiClient is the meta that the provider hands out to every resource and
+// data source. It embeds the Forem API client, whose HTTP transport has
+// been wrapped with the provider-level retry and rate limiting behaviour.
+// Since all resources share the same client, the rate limits and caches
+// apply to the whole Terraform run.
+type apiClient struct {
+	*dev.Client
+
+	// user is the authenticated user, set when the credentials are
+	// verified at configure time or on first use otherwise.
+	user     *dev.User
+	userMu   sync.Mutex
+	defaults providerDefaults
+
+	articles      *articleCache
+	organizations *organizationCache
+	users         *userCache
+}
+
+type apiClientOptions struct {
+	Host         string
+	Token        string
+	MaxRetries   int
+	RetryMaxWait time.Duration
+
+	RequestTimeout time.Duration
+	Transport      httpTransportOptions
+
+	RequestsPerSecond      float64
+	Burst                  int
+	WriteRequestsPerSecond float64
+	WriteBurst             int
+}
+
+func newAPIClient(opts apiClientOptions) (*apiClient, error) {
+	c, err := dev.NewClient(dev.Options{Token: opts.Token, Host: opts.Host})
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newHTTPTransport(opts.Transport)
+	if err != nil {
+		return nil, err
+	}
+	limiter := &rateLimitTransport{
+		next:   &timeoutTransport{next: transport, timeout: opts.RequestTimeout},
+		all:    newTokenBucket(opts.RequestsPerSecond, opts.Burst),
+		writes: newTokenBucket(opts.WriteRequestsPerSecond, opts.WriteBurst),
+	}
+	c.Client = &http.Client{
+		Transport: newRetryTransport(limiter, opts.MaxRetries, opts.RetryMaxWait),
+	}
+	return &apiClient{
+		Client:        c,
+		articles:      newArticleCache(),
+		organizations: newOrganizationCache(),
+		users:         newUserCache(),
+	}, nil
+}
+
+// authenticatedUser returns the authenticated user, fetching it if the
+// credentials have not been verified at configure time.
+func (c *apiClient) authenticatedUser(ctx context.Context) (*dev.User, error) {
+	c.userMu.Lock()
+	defer c.userMu.Unlock()
+
+	if c.user != nil {
+		return c.user, nil
+	}
+	tflog.Debug(ctx, "Getting authenticated user")
+	user, err := c.GetAuthenticatedUser()
+	if err != nil {
+		return nil, err
+	}
+	c.user = user
+	return user, nil
+}
+
+// apiErrorCode returns the HTTP status code of a Forem API error, or `0` if
+// err is not one. dev.DevAPIError does not export the code, so it is parsed
+// from the error message, which always ends with it.
+func apiErrorCode(err error) int {
+	var apiErr *dev.DevAPIError
+	if !errors.As(err, &apiErr) {
+		return 0
+	}
+	msg := apiErr.Error()
+	code, err := strconv.Atoi(msg[strings.LastIndex(msg, " ")+1:])
+	if err != nil {
+		return 0
+	}
+	return code
+}
+
+// isNotFound reports whether err is a `404` response of the Forem API.
+func isNotFound(err error) bool {
+	return apiErrorCode(err) == http.StatusNotFound
+}
+
+// verifyCredentials fetches the authenticated user, telling apart a host that
+// is not a Forem API and an invalid API key. Network errors, rate limiting and
+// server side errors say nothing about either, so they are reported as such.
+// The request is sent without dev.Client.SendHttpRequest, since the error
+// responses of a host that is not a Forem API cannot be parsed by it.
+func (c *apiClient) verifyCredentials(ctx context.Context) (*dev.User, diag.Diagnostics) {
+	host := c.BaseUrl.String()
+	req, err := c.NewRequest(ctx, http.MethodGet, "/users/me", nil)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	req.Header.Set("api-key", c.Token)
+
+	resp, err := c.Client.Client.Do(req)
+	if err != nil {
+		return nil, unverifiedCredentialsDiagnostics(host, err.Error())
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Invalid Forem API key",
+			Detail:   fmt.Sprintf("Host `%s` rejected the API key with status code `%d`. Please make sure that the API key has been generated on the same Forem instance.", host, resp.StatusCode),
+		}}
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, unverifiedCredentialsDiagnostics(host, fmt.Sprintf("status code `%d`", resp.StatusCode))
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, invalidHostDiagnostics(host, fmt.Sprintf("status code `%d`", resp.StatusCode))
+	}
+
+	user := new(dev.User)
+	if err := json.NewDecoder(resp.Body).Decode(user); err != nil || user.Username == "" {
+		return nil, invalidHostDiagnostics(host, "a response that is not a Forem user")
+	}
+	return user, nil
+}
+
+func unverifiedCredentialsDiagnostics(host, reason string) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Unable to verify Forem credentials",
+		Detail:   fmt.Sprintf("could not verify credentials: %s. Host `%s` may be unreachable or temporarily unavailable, please try again later.", reason, host),
+	}}
+}
+
+func invalidHostDiagnostics(host, reason string) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Invalid Forem host",
+		Detail:   fmt.Sprintf("Host `%s` answered with %s. Please make sure that `host` points to the API of a Forem instance, e.g. `%s`.", host, reason, devToBaseURL),
+	}}
+}
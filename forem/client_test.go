@@ -0,0 +1,51 @@
+package forem
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestAPIClient(t *testing.T, handler http.HandlerFunc, maxRetries int) *apiClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := newAPIClient(apiClientOptions{
+		Host:         srv.URL,
+		Token:        "test",
+		MaxRetries:   maxRetries,
+		RetryMaxWait: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Client.Client.Transport.(*retryTransport).minWait = time.Millisecond
+	return c
+}
+
+func writeDevError(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":%q,"status":%d}`, http.StatusText(status), status)
+}
+
+func TestAPIErrorCode(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeDevError(w, http.StatusNotFound)
+	}, 0)
+
+	_, err := c.GetListingByID("1")
+	if code := apiErrorCode(err); code != http.StatusNotFound {
+		t.Errorf("expected code %d, got %d", http.StatusNotFound, code)
+	}
+	if !isNotFound(err) {
+		t.Error("expected error to be a not found error")
+	}
+	if code := apiErrorCode(fmt.Errorf("some error")); code != 0 {
+		t.Errorf("expected code 0 for a non API error, got %d", code)
+	}
+}
@@ -0,0 +1,180 @@
+package forem
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	defaultProfile         = "default"
+	defaultCredentialsFile = "~/.config/forem/credentials"
+	credentialsAPIKey      = "api_key"
+)
+
+// resolveAPIKey returns the API key from the first configured source. The
+// schema makes sure that at most one of `api_key`, `api_key_file`,
+// `credentials_command` and `profile` is set. If none of them is,
+// `FOREM_API_KEY` is used and finally the default profile of the
+// credentials file, if the file exists.
+func resolveAPIKey(ctx context.Context, d *schema.ResourceData) (string, diag.Diagnostics) {
+	if v, ok := d.GetOk("api_key"); ok {
+		return v.(string), nil
+	}
+	if v, ok := d.GetOk("api_key_file"); ok {
+		key, err := readAPIKeyFile(v.(string))
+		if err != nil {
+			return "", credentialsError("api_key_file", err)
+		}
+		return key, nil
+	}
+	if v, ok := d.GetOk("credentials_command"); ok {
+		args := []string{}
+		for _, a := range v.([]interface{}) {
+			args = append(args, a.(string))
+		}
+		key, err := runCredentialsCommand(ctx, args)
+		if err != nil {
+			return "", credentialsError("credentials_command", err)
+		}
+		return key, nil
+	}
+
+	credentialsFile := d.Get("credentials_file").(string)
+	if v, ok := d.GetOk("profile"); ok {
+		key, err := readProfileAPIKey(credentialsFile, v.(string))
+		if err != nil {
+			return "", credentialsError("profile", err)
+		}
+		return key, nil
+	}
+	if v := os.Getenv(envForemAPIKey); v != "" {
+		return v, nil
+	}
+	if path, err := expandHome(credentialsFile); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			key, err := readProfileAPIKey(credentialsFile, defaultProfile)
+			if err != nil {
+				return "", credentialsError("credentials_file", err)
+			}
+			return key, nil
+		}
+	}
+
+	return "", diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "No Forem API key configured",
+		Detail: fmt.Sprintf("Set one of `api_key`, `api_key_file`, `credentials_command` or `profile`, "+
+			"the `%s` environment variable or add a `%s` profile to `%s`.", envForemAPIKey, defaultProfile, credentialsFile),
+	}}
+}
+
+func credentialsError(attr string, err error) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Unable to read Forem API key",
+		Detail:   fmt.Sprintf("Unable to read the API key using `%s`: %s", attr, err),
+	}}
+}
+
+func readAPIKeyFile(path string) (string, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(b))
+	if key == "" {
+		return "", fmt.Errorf("file `%s` is empty", path)
+	}
+	return key, nil
+}
+
+func runCredentialsCommand(ctx context.Context, args []string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", fmt.Errorf("command `%s` printed nothing to stdout", args[0])
+	}
+	return key, nil
+}
+
+// readProfileAPIKey reads the API key of a profile from an INI style
+// credentials file:
+//
+//	[default]
+//	api_key = ...
+//
+//	[work]
+//	api_key = ...
+func readProfileAPIKey(path, profile string) (string, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	section := ""
+	found := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			found = found || section == profile
+			continue
+		}
+		if section != profile {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) == credentialsAPIKey {
+			if key := strings.TrimSpace(kv[1]); key != "" {
+				return key, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("profile `%s` not found in `%s`", profile, path)
+	}
+	return "", fmt.Errorf("profile `%s` in `%s` has no `%s`", profile, path, credentialsAPIKey)
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
@@ -0,0 +1,116 @@
+package forem
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testCredentialsFile = `
+# Forem credentials
+[default]
+api_key = default-key
+
+[work]
+api_key = work-key
+
+[empty]
+`
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveAPIKey(t *testing.T) {
+	credentialsFile := writeTestFile(t, "credentials", testCredentialsFile)
+	keyFile := writeTestFile(t, "key", "file-key\n")
+
+	cases := map[string]struct {
+		raw       map[string]interface{}
+		env       string
+		want      string
+		wantError bool
+	}{
+		"api_key": {
+			raw:  map[string]interface{}{"api_key": "literal-key"},
+			want: "literal-key",
+		},
+		"api_key_file": {
+			raw:  map[string]interface{}{"api_key_file": keyFile},
+			want: "file-key",
+		},
+		"missing api_key_file": {
+			raw:       map[string]interface{}{"api_key_file": filepath.Join(t.TempDir(), "missing")},
+			wantError: true,
+		},
+		"credentials_command": {
+			raw:  map[string]interface{}{"credentials_command": []interface{}{"echo", "command-key"}},
+			want: "command-key",
+		},
+		"failing credentials_command": {
+			raw:       map[string]interface{}{"credentials_command": []interface{}{"false"}},
+			wantError: true,
+		},
+		"profile": {
+			raw:  map[string]interface{}{"profile": "work", "credentials_file": credentialsFile},
+			want: "work-key",
+		},
+		"unknown profile": {
+			raw:       map[string]interface{}{"profile": "unknown", "credentials_file": credentialsFile},
+			wantError: true,
+		},
+		"profile without api_key": {
+			raw:       map[string]interface{}{"profile": "empty", "credentials_file": credentialsFile},
+			wantError: true,
+		},
+		"environment variable": {
+			raw:  map[string]interface{}{"credentials_file": credentialsFile},
+			env:  "env-key",
+			want: "env-key",
+		},
+		"default profile": {
+			raw:  map[string]interface{}{"credentials_file": credentialsFile},
+			want: "default-key",
+		},
+		"nothing configured": {
+			raw:       map[string]interface{}{"credentials_file": filepath.Join(t.TempDir(), "missing")},
+			wantError: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(envForemAPIKey, tc.env)
+			t.Setenv(envForemProfile, "")
+
+			d := schema.TestResourceDataRaw(t, Provider().Schema, tc.raw)
+			key, diags := resolveAPIKey(context.Background(), d)
+			if diags.HasError() != tc.wantError {
+				t.Fatalf("expected error: %t, got %v", tc.wantError, diags)
+			}
+			if key != tc.want {
+				t.Errorf("expected key `%s`, got `%s`", tc.want, key)
+			}
+		})
+	}
+}
+
+func TestProvider_credentialSourcesConflict(t *testing.T) {
+	raw := map[string]interface{}{
+		"api_key":      "literal-key",
+		"api_key_file": "/path/to/key",
+	}
+	diags := Provider().Validate(terraform.NewResourceConfigRaw(raw))
+	if !diags.HasError() {
+		t.Fatal("expected conflicting credential sources to fail validation")
+	}
+}
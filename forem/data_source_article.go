@@ -134,36 +134,15 @@ func dataSourceArticle() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
-			"user": {
-				Description: "User object of the article.",
-				Type:        schema.TypeMap,
-				Computed:    true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			"organization": {
-				Description: "Organization object of the article.",
-				Type:        schema.TypeMap,
-				Computed:    true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			"flare_tag": {
-				Description: "Flare tag object of the article.",
-				Type:        schema.TypeMap,
-				Computed:    true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
+			"user":         userSchema("User object of the article."),
+			"organization": organizationSchema("Organization object of the article."),
+			"flare_tag":    flareTagSchema("Flare tag object of the article."),
 		},
 	}
 }
 
 func dataSourceArticleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
 	var articlesResp *dev.ArticleVariant
 	var err error
@@ -211,34 +190,19 @@ func dataSourceArticleRead(ctx context.Context, d *schema.ResourceData, meta int
 	d.Set("last_comment_at", articlesResp.Article.LastCommentAt)
 	d.Set("published_timestamp", articlesResp.Article.PublishedTimestamp)
 
-	if articlesResp.User != nil {
-		d.Set("user", map[string]interface{}{
-			"name":             articlesResp.User.Name,
-			"username":         articlesResp.User.Username,
-			"twitter_username": articlesResp.User.TwitterUsername,
-			"github_username":  articlesResp.User.GithubUsername,
-			"website_url":      articlesResp.User.WebsiteURL,
-			"profile_image":    articlesResp.User.ProfileImage,
-		})
+	user, err := flattenUser(ctx, client, articlesResp.User)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	d.Set("user", user)
 
-	if articlesResp.Organization != nil {
-		d.Set("user", map[string]interface{}{
-			"name":             articlesResp.Organization.Name,
-			"username":         articlesResp.Organization.Username,
-			"slug":             articlesResp.Organization.Slug,
-			"profile_image":    articlesResp.Organization.ProfileImage,
-			"profile_image_90": articlesResp.Organization.ProfileImage90,
-		})
+	organization, err := flattenOrganization(ctx, client, articlesResp.Organization)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	d.Set("organization", organization)
 
-	if articlesResp.FlareTag != nil {
-		d.Set("flare_tag", map[string]interface{}{
-			"name":           articlesResp.FlareTag.Name,
-			"bg_color_hex":   articlesResp.FlareTag.BGColorHEX,
-			"text_color_hex": articlesResp.FlareTag.TextColorHEX,
-		})
-	}
+	d.Set("flare_tag", flattenFlareTag(articlesResp.FlareTag))
 
 	return nil
 }
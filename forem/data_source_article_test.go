@@ -26,7 +26,7 @@ func TestAccArticleDataSource_queryByID(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resourceName, "path"),
 					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
 					resource.TestCheckNoResourceAttr(resourceName, "updated_at"),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 					resource.TestCheckResourceAttrSet(resourceName, "published_at"),
 					resource.TestCheckResourceAttrSet(resourceName, "published_timestamp"),
 					resource.TestCheckResourceAttrSet(resourceName, "comments_count"),
@@ -57,7 +57,7 @@ func TestAccArticleDataSource_queryByUsernameAndSlug(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "slug", articleSlug),
 					resource.TestCheckResourceAttrSet(resourceName, "path"),
 					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 					resource.TestCheckResourceAttrSet(resourceName, "published_at"),
 					resource.TestCheckResourceAttrSet(resourceName, "published_timestamp"),
 					resource.TestCheckResourceAttrSet(resourceName, "comments_count"),
@@ -8,7 +8,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	dev "github.com/karvounis/dev-client-go"
 )
 
 const (
@@ -51,7 +50,7 @@ func dataSourceFollowedTags() *schema.Resource {
 }
 
 func dataSourceFollowedTagsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
 	tflog.Debug(ctx, "Getting followed tags")
 	ftResp, err := client.GetFollowedTags()
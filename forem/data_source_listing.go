@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	dev "github.com/karvounis/dev-client-go"
 )
 
 func dataSourceListing() *schema.Resource {
@@ -53,24 +52,14 @@ func dataSourceListing() *schema.Resource {
 				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
-			"user": {
-				Description: "User that has created this listing.",
-				Type:        schema.TypeMap,
-				Computed:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"organization": {
-				Description: "Organization related to this listing.",
-				Type:        schema.TypeMap,
-				Computed:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
+			"user":         userSchema("User that has created this listing."),
+			"organization": organizationSchema("Organization related to this listing."),
 		},
 	}
 }
 
 func dataSourceListingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
 	id := d.Get("id").(string)
 	tflog.Debug(ctx, fmt.Sprintf("Getting listing: %s", id))
@@ -88,26 +77,17 @@ func dataSourceListingRead(ctx context.Context, d *schema.ResourceData, meta int
 	d.Set("published", listingResp.Published)
 	d.Set("tags", listingResp.Tags)
 
-	if listingResp.User != nil {
-		d.Set("user", map[string]interface{}{
-			"name":             listingResp.User.Name,
-			"username":         listingResp.User.Username,
-			"twitter_username": listingResp.User.TwitterUsername,
-			"github_username":  listingResp.User.GithubUsername,
-			"website_url":      listingResp.User.WebsiteURL,
-			"profile_image":    listingResp.User.ProfileImage,
-		})
+	user, err := flattenUser(ctx, client, listingResp.User)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	d.Set("user", user)
 
-	if listingResp.Organization != nil {
-		d.Set("organization", map[string]interface{}{
-			"name":             listingResp.Organization.Name,
-			"username":         listingResp.Organization.Username,
-			"slug":             listingResp.Organization.Slug,
-			"profile_image":    listingResp.Organization.ProfileImage,
-			"profile_image_90": listingResp.Organization.ProfileImage90,
-		})
+	organization, err := flattenOrganization(ctx, client, listingResp.Organization)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	d.Set("organization", organization)
 
 	return nil
 }
@@ -75,7 +75,7 @@ func dataSourceUser() *schema.Resource {
 }
 
 func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
 	var userResp *dev.User
 	var err error
@@ -0,0 +1,135 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// providerDefaults holds the `defaults` block of the provider, which is
+// merged into every forem_article and forem_listing.
+type providerDefaults struct {
+	OrganizationID     int
+	Tags               []string
+	Series             string
+	CanonicalURLPrefix string
+}
+
+func getProviderDefaults(d *schema.ResourceData) providerDefaults {
+	var defaults providerDefaults
+	v, ok := d.GetOk("defaults")
+	if !ok {
+		return defaults
+	}
+	l := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return defaults
+	}
+	m := l[0].(map[string]interface{})
+
+	defaults.OrganizationID = m["organization_id"].(int)
+	defaults.Tags = normalizeTags(expandStringList(m["tags"].([]interface{})))
+	defaults.Series = m["series"].(string)
+	defaults.CanonicalURLPrefix = m["canonical_url_prefix"].(string)
+	return defaults
+}
+
+// mergeTags returns the tags followed by the default tags that are not
+// already part of them.
+func mergeTags(tags, defaultTags []string) []string {
+	merged := append([]string{}, tags...)
+	for _, t := range defaultTags {
+		if !containsString(merged, t) {
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+// withoutDefaultTags returns the effective tags of a resource minus the
+// default tags that were not configured on the resource itself.
+func withoutDefaultTags(tagsAll, configured, defaultTags []string) []string {
+	tags := []string{}
+	for _, t := range tagsAll {
+		if containsString(configured, t) || !containsString(defaultTags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// customizeDiffTagsAll plans `tags_all`, i.e. the tags of the resource merged
+// with the default tags of the provider, and makes sure they are unique and
+// do not exceed maxTags. resourceDefaults, if not nil, returns the defaults of the resource
+// itself and whether they are known yet.
+func customizeDiffTagsAll(maxTags int, resourceDefaults func(*schema.ResourceDiff, providerDefaults) (providerDefaults, bool)) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !d.NewValueKnown("tags") {
+			return d.SetNewComputed("tags_all")
+		}
+
+		client := meta.(*apiClient)
+		defaults := client.defaults
+		if resourceDefaults != nil {
+			var known bool
+			if defaults, known = resourceDefaults(d, defaults); !known {
+				return d.SetNewComputed("tags_all")
+			}
+		}
+		tags := expandStringList(d.Get("tags").([]interface{}))
+		if tag, ok := duplicateTag(tags); ok {
+			return fmt.Errorf("duplicate tag `%s` in `tags`", tag)
+		}
+		tags = mergeTags(normalizeTags(tags), defaults.Tags)
+		if len(tags) > maxTags {
+			return fmt.Errorf("too many tags: %d tags including the default tags of the provider, maximum is %d", len(tags), maxTags)
+		}
+
+		old, _ := d.GetChange("tags_all")
+		if !equalStrings(expandStringList(old.([]interface{})), tags) {
+			return d.SetNew("tags_all", tags)
+		}
+		return nil
+	}
+}
+
+// defaultCanonicalURL builds the canonical URL of an article out of the
+// `canonical_url_prefix` of the provider and the title of the article.
+func defaultCanonicalURL(prefix, title string) string {
+	slug := strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	return strings.TrimRight(prefix, "/") + "/" + slug
+}
+
+func expandStringList(l []interface{}) []string {
+	s := []string{}
+	for _, v := range l {
+		s = append(s, v.(string))
+	}
+	return s
+}
+
+func containsString(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package forem
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestMergeTags(t *testing.T) {
+	got := mergeTags([]string{"go", "terraform"}, []string{"terraform", "forem"})
+	want := []string{"go", "terraform", "forem"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestWithoutDefaultTags(t *testing.T) {
+	got := withoutDefaultTags([]string{"go", "terraform", "forem"}, []string{"go", "terraform"}, []string{"terraform", "forem"})
+	want := []string{"go", "terraform"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDefaultCanonicalURL(t *testing.T) {
+	got := defaultCanonicalURL("https://blog.example.com/posts/", "Hello, Terraform & Forem!")
+	want := "https://blog.example.com/posts/hello-terraform-forem"
+	if got != want {
+		t.Errorf("expected `%s`, got `%s`", want, got)
+	}
+}
+
+func TestGetProviderDefaults(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"defaults": []interface{}{map[string]interface{}{
+			"organization_id":      42,
+			"tags":                 []interface{}{"forem"},
+			"series":               "house series",
+			"canonical_url_prefix": "https://blog.example.com",
+		}},
+	})
+	got := getProviderDefaults(d)
+	want := providerDefaults{
+		OrganizationID:     42,
+		Tags:               []string{"forem"},
+		Series:             "house series",
+		CanonicalURLPrefix: "https://blog.example.com",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestGetArticleBodySchemaFromResourceData_defaults(t *testing.T) {
+	defaults := providerDefaults{
+		OrganizationID:     42,
+		Tags:               []string{"forem", "terraform"},
+		Series:             "house series",
+		CanonicalURLPrefix: "https://blog.example.com",
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":         "My Article",
+		"body_markdown": "body",
+		"tags":          []interface{}{"go", "terraform"},
+	})
+	abc := getArticleBodySchemaFromResourceData(d, defaults)
+	if want := []string{"go", "terraform", "forem"}; !reflect.DeepEqual(abc.Article.Tags, want) {
+		t.Errorf("expected tags %v, got %v", want, abc.Article.Tags)
+	}
+	if abc.Article.Series != defaults.Series {
+		t.Errorf("expected series `%s`, got `%s`", defaults.Series, abc.Article.Series)
+	}
+	if want := "https://blog.example.com/my-article"; abc.Article.CanonicalURL != want {
+		t.Errorf("expected canonical URL `%s`, got `%s`", want, abc.Article.CanonicalURL)
+	}
+	if abc.Article.OrganizationID != 42 {
+		t.Errorf("expected organization ID 42, got %d", abc.Article.OrganizationID)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":         "My Article",
+		"body_markdown": "body",
+		"series":        "own series",
+		"canonical_url": "https://example.com/own",
+	})
+	abc = getArticleBodySchemaFromResourceData(d, defaults)
+	if abc.Article.Series != "own series" || abc.Article.CanonicalURL != "https://example.com/own" {
+		t.Errorf("expected resource values to take precedence, got %+v", abc.Article)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":           "My Article",
+		"body_markdown":   "body",
+		"organization_id": 7,
+	})
+	abc = getArticleBodySchemaFromResourceData(d, defaults)
+	if abc.Article.OrganizationID != 7 {
+		t.Errorf("expected organization ID 7, got %d", abc.Article.OrganizationID)
+	}
+}
+
+func TestGetListingBodySchemaFromResourceData_defaults(t *testing.T) {
+	defaults := providerDefaults{OrganizationID: 42, Tags: []string{"forem"}}
+
+	d := schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+		"title":         "listing",
+		"body_markdown": "body",
+		"category":      "cfp",
+	})
+	lbc, err := getListingBodySchemaFromResourceData(d, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"forem"}; !reflect.DeepEqual(lbc.Listing.Tags, want) {
+		t.Errorf("expected tags %v, got %v", want, lbc.Listing.Tags)
+	}
+	if lbc.Listing.OrganizationID != 42 {
+		t.Errorf("expected organization ID 42, got %d", lbc.Listing.OrganizationID)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+		"title":           "listing",
+		"body_markdown":   "body",
+		"category":        "cfp",
+		"organization_id": 7,
+	})
+	lbc, err = getListingBodySchemaFromResourceData(d, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lbc.Listing.OrganizationID != 7 {
+		t.Errorf("expected organization ID 7, got %d", lbc.Listing.OrganizationID)
+	}
+}
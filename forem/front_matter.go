@@ -0,0 +1,267 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	frontMatterStrip  = "strip"
+	frontMatterMerge  = "merge"
+	frontMatterReject = "reject"
+)
+
+var allowedFrontMatterModes = []string{frontMatterStrip, frontMatterMerge, frontMatterReject}
+
+var frontMatterRegexp = regexp.MustCompile(`(?s)\A---[ \t]*\r?\n(.*?)(?:\r?\n)?---[ \t]*(?:\r?\n|\z)`)
+
+// frontMatter is the Jekyll-style front matter of the body of an article,
+// which Forem applies on top of the attributes of the article.
+type frontMatter struct {
+	Title        string
+	Published    *bool
+	Tags         []string
+	Series       string
+	Description  string
+	CoverImage   string
+	CanonicalURL string
+}
+
+type rawFrontMatter struct {
+	Title        string      `yaml:"title"`
+	Published    *bool       `yaml:"published"`
+	Tags         interface{} `yaml:"tags"`
+	Series       string      `yaml:"series"`
+	Description  string      `yaml:"description"`
+	CoverImage   string      `yaml:"cover_image"`
+	CanonicalURL string      `yaml:"canonical_url"`
+}
+
+// resourceGetter is implemented by both schema.ResourceData and
+// schema.ResourceDiff.
+type resourceGetter interface {
+	Get(key string) interface{}
+}
+
+// splitFrontMatter splits the body of an article into its front matter
+// header, without the `---` delimiters, and the rest of the body.
+func splitFrontMatter(body string) (string, string, bool) {
+	loc := frontMatterRegexp.FindStringSubmatchIndex(body)
+	if loc == nil {
+		return "", body, false
+	}
+	return body[loc[2]:loc[3]], body[loc[1]:], true
+}
+
+// stripFrontMatter returns the body of an article without its front matter.
+func stripFrontMatter(body string) string {
+	_, rest, _ := splitFrontMatter(body)
+	return rest
+}
+
+// decodeFrontMatter parses the YAML front matter header of an article.
+func decodeFrontMatter(header string) (*frontMatter, error) {
+	var raw rawFrontMatter
+	if err := yaml.Unmarshal([]byte(header), &raw); err != nil {
+		return nil, fmt.Errorf("invalid front matter: %s", err)
+	}
+
+	fm := &frontMatter{
+		Title:        raw.Title,
+		Published:    raw.Published,
+		Series:       raw.Series,
+		Description:  raw.Description,
+		CoverImage:   raw.CoverImage,
+		CanonicalURL: raw.CanonicalURL,
+	}
+	switch tags := raw.Tags.(type) {
+	case nil:
+	case string:
+		for _, t := range strings.Split(tags, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				fm.Tags = append(fm.Tags, t)
+			}
+		}
+	case []interface{}:
+		for _, t := range tags {
+			if t == nil {
+				continue
+			}
+			if tag := strings.TrimSpace(fmt.Sprint(t)); tag != "" {
+				fm.Tags = append(fm.Tags, tag)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid front matter: `tags` must be a comma-separated string or a list")
+	}
+	return fm, nil
+}
+
+// getFrontMatter returns the decoded front matter of the body of an article,
+// or nil if it has none.
+func getFrontMatter(d resourceGetter) (*frontMatter, error) {
+	body, err := getBody(d)
+	if err != nil {
+		return nil, err
+	}
+	header, _, ok := splitFrontMatter(body)
+	if !ok {
+		return nil, nil
+	}
+	return decodeFrontMatter(header)
+}
+
+// mergedFrontMatter returns the front matter whose values are used along with
+// the arguments of an article, which is empty unless `front_matter` is
+// `merge`.
+func mergedFrontMatter(d resourceGetter) *frontMatter {
+	if d.Get("front_matter").(string) != frontMatterMerge {
+		return &frontMatter{}
+	}
+	fm, err := getFrontMatter(d)
+	if err != nil || fm == nil {
+		return &frontMatter{}
+	}
+	return fm
+}
+
+// frontMatterDefaults returns the defaults of an article, which with `merge`
+// are overlaid with the series and tags of its front matter.
+func frontMatterDefaults(d resourceGetter, defaults providerDefaults) providerDefaults {
+	if d.Get("front_matter").(string) != frontMatterMerge {
+		return defaults
+	}
+	fm, err := getFrontMatter(d)
+	if err != nil || fm == nil {
+		return defaults
+	}
+	if fm.Series != "" {
+		defaults.Series = fm.Series
+	}
+	defaults.Tags = mergeTags(normalizeTags(fm.Tags), defaults.Tags)
+	return defaults
+}
+
+// validateFrontMatter makes sure that the front matter of an article can be
+// reconciled with its arguments, according to `front_matter`.
+func validateFrontMatter(d resourceGetter) error {
+	mode := d.Get("front_matter").(string)
+	body, err := getBody(d)
+	if err != nil {
+		return err
+	}
+	header, _, ok := splitFrontMatter(body)
+	if !ok || mode == frontMatterStrip {
+		return nil
+	}
+	if mode == frontMatterReject {
+		return fmt.Errorf("the body has a front matter header, which is not allowed since `front_matter` is set to `%s`", frontMatterReject)
+	}
+
+	fm, err := decodeFrontMatter(header)
+	if err != nil {
+		return err
+	}
+	if fm.Title != "" && fm.Title != d.Get("title").(string) {
+		return fmt.Errorf("front matter title `%s` conflicts with `title`", fm.Title)
+	}
+	if fm.Published != nil && *fm.Published != d.Get("published").(bool) {
+		return fmt.Errorf("front matter published `%t` conflicts with `published`", *fm.Published)
+	}
+	if series := d.Get("series").(string); fm.Series != "" && series != "" && fm.Series != series {
+		return fmt.Errorf("front matter series `%s` conflicts with `series`", fm.Series)
+	}
+	for _, t := range fm.Tags {
+		if err := checkTag(t); err != nil {
+			return fmt.Errorf("invalid front matter: %w", err)
+		}
+	}
+	fallbacks := []struct{ key, value string }{
+		{"description", fm.Description},
+		{"cover_image", fm.CoverImage},
+		{"canonical_url", fm.CanonicalURL},
+	}
+	for _, f := range fallbacks {
+		if v := d.Get(f.key).(string); f.value != "" && v != "" && f.value != v {
+			return fmt.Errorf("front matter %s `%s` conflicts with `%s`", f.key, f.value, f.key)
+		}
+	}
+	return nil
+}
+
+// flattenFrontMatter returns the `parsed_front_matter` of an article.
+func flattenFrontMatter(fm *frontMatter) []interface{} {
+	if fm == nil {
+		return []interface{}{}
+	}
+	m := map[string]interface{}{
+		"title":         fm.Title,
+		"tags":          fm.Tags,
+		"series":        fm.Series,
+		"description":   fm.Description,
+		"cover_image":   fm.CoverImage,
+		"canonical_url": fm.CanonicalURL,
+	}
+	if fm.Published != nil {
+		m["published"] = *fm.Published
+	}
+	return []interface{}{m}
+}
+
+// frontMatterSeriesChanged reports whether the series of the front matter of
+// an article changes with the update, when it is used with `merge`.
+func frontMatterSeriesChanged(d *schema.ResourceData) bool {
+	return frontMatterDefaults(priorValues{d}, providerDefaults{}).Series != frontMatterDefaults(d, providerDefaults{}).Series
+}
+
+// frontMatterFieldChanged reports whether a value of the front matter of an
+// article, which is used as a fallback with `merge`, changes with the update.
+func frontMatterFieldChanged(d *schema.ResourceData, value func(*frontMatter) string) bool {
+	return value(mergedFrontMatter(priorValues{d})) != value(mergedFrontMatter(d))
+}
+
+// priorValues reads the values of a schema.ResourceData before the update.
+type priorValues struct {
+	d *schema.ResourceData
+}
+
+func (p priorValues) Get(key string) interface{} {
+	v, _ := p.d.GetChange(key)
+	return v
+}
+
+// frontMatterDiffDefaults returns the defaults of an article being planned,
+// and whether they are known yet.
+func frontMatterDiffDefaults(d *schema.ResourceDiff, defaults providerDefaults) (providerDefaults, bool) {
+	if !bodyKnown(d) || !d.NewValueKnown("front_matter") {
+		return defaults, false
+	}
+	return frontMatterDefaults(d, defaults), true
+}
+
+// customizeDiffFrontMatter validates the front matter of an article and plans
+// its `parsed_front_matter`.
+func customizeDiffFrontMatter(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !bodyKnown(d) {
+		return d.SetNewComputed("parsed_front_matter")
+	}
+	for _, k := range []string{"front_matter", "title", "published", "series", "description", "cover_image", "canonical_url"} {
+		if !d.NewValueKnown(k) {
+			return d.SetNewComputed("parsed_front_matter")
+		}
+	}
+	if err := validateFrontMatter(d); err != nil {
+		return err
+	}
+	// body_sha256 has been planned by customizeDiffBodySHA256 already.
+	if !d.HasChange("body_sha256") {
+		return nil
+	}
+	fm, _ := getFrontMatter(d)
+	return d.SetNew("parsed_front_matter", flattenFrontMatter(fm))
+}
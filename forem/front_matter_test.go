@@ -0,0 +1,276 @@
+package forem
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testFrontMatterBody = `---
+title: My Article
+published: true
+tags: go, terraform
+series: house series
+---
+Hello, Forem!
+`
+
+const testFrontMatterFallbacksBody = `---
+description: About Forem
+cover_image: https://example.com/cover.png
+canonical_url: https://example.com/my-article
+---
+Hello, Forem!
+`
+
+func TestSplitFrontMatter(t *testing.T) {
+	cases := map[string]struct {
+		body       string
+		wantHeader string
+		wantRest   string
+		wantOK     bool
+	}{
+		"front matter": {
+			body:       testFrontMatterBody,
+			wantHeader: "title: My Article\npublished: true\ntags: go, terraform\nseries: house series",
+			wantRest:   "Hello, Forem!\n",
+			wantOK:     true,
+		},
+		"empty front matter": {
+			body:     "---\n---\nbody",
+			wantRest: "body",
+			wantOK:   true,
+		},
+		"windows line endings": {
+			body:       "---\r\ntitle: x\r\n---\r\nbody",
+			wantHeader: "title: x",
+			wantRest:   "body",
+			wantOK:     true,
+		},
+		"no front matter": {
+			body:     "body\n---\ntitle: x\n---\n",
+			wantRest: "body\n---\ntitle: x\n---\n",
+		},
+		"unterminated front matter": {
+			body:     "---\ntitle: x\nbody",
+			wantRest: "---\ntitle: x\nbody",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			header, rest, ok := splitFrontMatter(tc.body)
+			if header != tc.wantHeader || rest != tc.wantRest || ok != tc.wantOK {
+				t.Errorf("expected %q, %q, %t, got %q, %q, %t", tc.wantHeader, tc.wantRest, tc.wantOK, header, rest, ok)
+			}
+		})
+	}
+}
+
+func TestDecodeFrontMatter(t *testing.T) {
+	published := true
+	cases := map[string]struct {
+		header    string
+		want      *frontMatter
+		wantError bool
+	}{
+		"comma-separated tags": {
+			header: "title: My Article\npublished: true\ntags: go, terraform\nseries: house series",
+			want:   &frontMatter{Title: "My Article", Published: &published, Tags: []string{"go", "terraform"}, Series: "house series"},
+		},
+		"list of tags": {
+			header: "tags:\n  - go\n  - terraform",
+			want:   &frontMatter{Tags: []string{"go", "terraform"}},
+		},
+		"invalid tags": {
+			header:    "tags:\n  go: terraform",
+			wantError: true,
+		},
+		"invalid yaml": {
+			header:    "title: [My Article",
+			wantError: true,
+		},
+		"not a mapping": {
+			header:    "- title",
+			wantError: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fm, err := decodeFrontMatter(tc.header)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("expected error: %t, got %v", tc.wantError, err)
+			}
+			if !reflect.DeepEqual(fm, tc.want) {
+				t.Errorf("expected %+v, got %+v", tc.want, fm)
+			}
+		})
+	}
+}
+
+func TestValidateFrontMatter(t *testing.T) {
+	cases := map[string]struct {
+		raw       map[string]interface{}
+		body      string
+		wantError string
+	}{
+		"merge": {
+			raw: map[string]interface{}{"title": "My Article", "published": true},
+		},
+		"merge with conflicting title": {
+			raw:       map[string]interface{}{"title": "Another Article", "published": true},
+			wantError: "front matter title `My Article` conflicts with `title`",
+		},
+		"merge with conflicting published": {
+			raw:       map[string]interface{}{"title": "My Article"},
+			wantError: "front matter published `true` conflicts with `published`",
+		},
+		"merge with conflicting series": {
+			raw:       map[string]interface{}{"title": "My Article", "published": true, "series": "another series"},
+			wantError: "front matter series `house series` conflicts with `series`",
+		},
+		"strip": {
+			raw: map[string]interface{}{"title": "Another Article", "front_matter": frontMatterStrip},
+		},
+		"merge with fallbacks": {
+			raw:  map[string]interface{}{"title": "My Article", "description": "About Forem"},
+			body: testFrontMatterFallbacksBody,
+		},
+		"merge with conflicting cover image": {
+			raw:       map[string]interface{}{"title": "My Article", "cover_image": "https://example.com/other.png"},
+			body:      testFrontMatterFallbacksBody,
+			wantError: "front matter cover_image `https://example.com/cover.png` conflicts with `cover_image`",
+		},
+		"reject": {
+			raw:       map[string]interface{}{"title": "My Article", "published": true, "front_matter": frontMatterReject},
+			wantError: "not allowed",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.raw["body_markdown"] = testFrontMatterBody
+			if tc.body != "" {
+				tc.raw["body_markdown"] = tc.body
+			}
+			d := schema.TestResourceDataRaw(t, resourceArticle().Schema, tc.raw)
+
+			err := validateFrontMatter(d)
+			if tc.wantError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+				t.Fatalf("expected error containing `%s`, got %v", tc.wantError, err)
+			}
+		})
+	}
+}
+
+func TestGetArticleBodySchemaFromResourceData_frontMatter(t *testing.T) {
+	defaults := providerDefaults{Tags: []string{"forem"}}
+
+	d := schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":         "My Article",
+		"body_markdown": testFrontMatterBody,
+		"published":     true,
+		"tags":          []interface{}{"api"},
+	})
+	abc := getArticleBodySchemaFromResourceData(d, defaults)
+	if abc.Article.BodyMarkdown != "Hello, Forem!\n" {
+		t.Errorf("expected front matter to be stripped, got %q", abc.Article.BodyMarkdown)
+	}
+	if want := []string{"api", "go", "terraform", "forem"}; !reflect.DeepEqual(abc.Article.Tags, want) {
+		t.Errorf("expected tags %v, got %v", want, abc.Article.Tags)
+	}
+	if abc.Article.Series != "house series" {
+		t.Errorf("expected front matter series, got `%s`", abc.Article.Series)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":         "My Article",
+		"body_markdown": testFrontMatterBody,
+		"front_matter":  frontMatterStrip,
+	})
+	abc = getArticleBodySchemaFromResourceData(d, defaults)
+	if abc.Article.BodyMarkdown != "Hello, Forem!\n" || abc.Article.Series != "" || !reflect.DeepEqual(abc.Article.Tags, []string{"forem"}) {
+		t.Errorf("expected front matter to be ignored, got %+v", abc.Article)
+	}
+}
+
+func TestGetArticleBodySchemaFromResourceData_frontMatterFallbacks(t *testing.T) {
+	defaults := providerDefaults{CanonicalURLPrefix: "https://blog.example.com"}
+
+	d := schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":         "My Article",
+		"body_markdown": testFrontMatterFallbacksBody,
+	})
+	abc := getArticleBodySchemaFromResourceData(d, defaults)
+	if abc.Article.Description != "About Forem" || abc.Article.MainImage != "https://example.com/cover.png" || abc.Article.CanonicalURL != "https://example.com/my-article" {
+		t.Errorf("expected front matter fallbacks, got %+v", abc.Article)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":         "My Article",
+		"body_markdown": testFrontMatterFallbacksBody,
+		"description":   "About the provider",
+		"cover_image":   "https://example.com/other.png",
+		"canonical_url": "https://example.com/other",
+	})
+	abc = getArticleBodySchemaFromResourceData(d, defaults)
+	if abc.Article.Description != "About the provider" || abc.Article.MainImage != "https://example.com/other.png" || abc.Article.CanonicalURL != "https://example.com/other" {
+		t.Errorf("expected arguments to take precedence, got %+v", abc.Article)
+	}
+}
+
+func FuzzSplitFrontMatter(f *testing.F) {
+	f.Add(testFrontMatterBody)
+	f.Add("---\n---\n")
+	f.Add("---\r\ntitle: x\r\n---")
+	f.Add("---\ntitle: [x\n---\nbody")
+	f.Add("---\ntags:\n  go: x\n---\n")
+	f.Add("body\n---\n")
+	f.Fuzz(func(t *testing.T, body string) {
+		header, rest, ok := splitFrontMatter(body)
+		if !ok {
+			if header != "" || rest != body {
+				t.Fatalf("expected body to be left as is without front matter, got %q, %q", header, rest)
+			}
+			return
+		}
+		if !strings.HasPrefix(body, "---") || !strings.HasSuffix(body, rest) || !strings.Contains(body, header) {
+			t.Fatalf("unexpected split of %q into %q and %q", body, header, rest)
+		}
+		if stripFrontMatter(body) != rest {
+			t.Fatalf("expected stripped body %q, got %q", rest, stripFrontMatter(body))
+		}
+		// Malformed headers must result in an error, never in a panic.
+		_, _ = decodeFrontMatter(header)
+	})
+}
+
+func FuzzDecodeFrontMatter(f *testing.F) {
+	f.Add("title: My Article\npublished: true\ntags: go, terraform")
+	f.Add("tags:\n  - go\n  - 1\n  - null")
+	f.Add("tags: {go: terraform}")
+	f.Add("published: maybe")
+	f.Add("- title")
+	f.Add("title: [My Article")
+	f.Add("&a [*a]")
+	f.Fuzz(func(t *testing.T, header string) {
+		fm, err := decodeFrontMatter(header)
+		if err != nil {
+			if fm != nil {
+				t.Fatalf("expected no front matter along with error %s", err)
+			}
+			return
+		}
+		for _, tag := range fm.Tags {
+			if tag == "" || tag != strings.TrimSpace(tag) {
+				t.Fatalf("expected tags of %q to be trimmed and not empty, got %q", header, fm.Tags)
+			}
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package forem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	dev "github.com/karvounis/dev-client-go"
+)
+
+const (
+	// listingExpiresAtLayout is the format of `expires_at` in the schema.
+	listingExpiresAtLayout = "01/02/2006"
+	// maxListingLifetimeDays is how many days ahead Forem accepts
+	// `expires_at`.
+	maxListingLifetimeDays = 30
+)
+
+// listingExpiresAtAPILayouts are the formats of `expires_at` in the
+// responses of the API.
+var listingExpiresAtAPILayouts = []string{time.RFC3339, "2006-01-02", listingExpiresAtLayout}
+
+// listing is a listing as returned by the API, including the fields that
+// dev.Listing does not decode. They are optional, since the API leaves them
+// out of some of its responses.
+type listing struct {
+	dev.Listing
+	ExpiresAt         optionalString `json:"expires_at"`
+	Location          optionalString `json:"location"`
+	ContactViaConnect *bool          `json:"contact_via_connect"`
+	OrganizationID    *int           `json:"organization_id"`
+}
+
+// optionalString is a string of the API that tells apart a field that is
+// left out of the response from a null one, which is decoded as empty.
+type optionalString struct {
+	Present bool
+	Value   string
+}
+
+func (s *optionalString) UnmarshalJSON(b []byte) error {
+	s.Present = true
+	if string(b) == "null" {
+		s.Value = ""
+		return nil
+	}
+	return json.Unmarshal(b, &s.Value)
+}
+
+// getListing returns the listing with the given ID.
+// dev.Client.GetListingByID drops the fields that are not part of dev.Listing.
+func (c *apiClient) getListing(ctx context.Context, id string) (*listing, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, "/listings/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	l := new(listing)
+	if err := c.SendHttpRequest(req, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// flattenListingExpiresAt converts the `expires_at` of the API into the
+// format of the schema.
+func flattenListingExpiresAt(expiresAt string) (string, error) {
+	if expiresAt == "" {
+		return "", nil
+	}
+	for _, layout := range listingExpiresAtAPILayouts {
+		if t, err := time.Parse(layout, expiresAt); err == nil {
+			return t.Format(listingExpiresAtLayout), nil
+		}
+	}
+	return "", fmt.Errorf("unexpected format of `expires_at`: `%s`", expiresAt)
+}
+
+// parseListingExpiresAt parses the `expires_at` of the schema, which is either
+// a `MM/DD/YYYY` date or an RFC3339 timestamp, into a date.
+func parseListingExpiresAt(expiresAt string) (time.Time, error) {
+	for _, layout := range []string{listingExpiresAtLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, expiresAt); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected a date in the format `MM/DD/YYYY` or an RFC3339 timestamp, got `%s`", expiresAt)
+}
+
+func validateListingExpiresAt(v interface{}, k string) ([]string, []error) {
+	expiresAt, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseListingExpiresAt(expiresAt); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// normalizeListingExpiresAtStateFunc stores `expires_at` in the format that
+// is read back from the API.
+func normalizeListingExpiresAtStateFunc(v interface{}) string {
+	t, err := parseListingExpiresAt(v.(string))
+	if err != nil {
+		return v.(string)
+	}
+	return t.Format(listingExpiresAtLayout)
+}
+
+// checkListingExpiresAt makes sure that the listing expires between today and
+// the maximum lifetime of a listing.
+func checkListingExpiresAt(expiresAt, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if expiresAt.Before(today) {
+		return fmt.Errorf("`expires_at` `%s` is in the past", expiresAt.Format(listingExpiresAtLayout))
+	}
+	if expiresAt.After(today.AddDate(0, 0, maxListingLifetimeDays)) {
+		return fmt.Errorf("`expires_at` `%s` is more than %d days ahead, which is the maximum lifetime of a listing", expiresAt.Format(listingExpiresAtLayout), maxListingLifetimeDays)
+	}
+	return nil
+}
+
+// listingExpired reports whether a listing that expires at the end of the
+// given day has expired.
+func listingExpired(expiresAt string, now time.Time) bool {
+	t, err := parseListingExpiresAt(expiresAt)
+	if err != nil {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return t.Before(today)
+}
+
+// customizeDiffListingExpiresAt checks the range of `expires_at` when it
+// changes, since a listing keeps its expiration date once it has passed.
+func customizeDiffListingExpiresAt(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("expires_at") || !d.HasChange("expires_at") {
+		return nil
+	}
+	expiresAt := d.Get("expires_at").(string)
+	if expiresAt == "" {
+		return nil
+	}
+	t, err := parseListingExpiresAt(expiresAt)
+	if err != nil {
+		return err
+	}
+	return checkListingExpiresAt(t, time.Now())
+}
+
+// bumpTriggered reports whether the update of the listing changes its
+// `bump_triggers`, other than by removing them.
+func bumpTriggered(d resourceChanger) bool {
+	return d.HasChange("bump_triggers") && len(d.Get("bump_triggers").(map[string]interface{})) > 0
+}
+
+// resourceChanger is implemented by both schema.ResourceData and
+// schema.ResourceDiff.
+type resourceChanger interface {
+	resourceGetter
+	HasChange(key string) bool
+}
+
+// customizeDiffBumpTriggers shows in the plan that the listing is about to be
+// bumped.
+func customizeDiffBumpTriggers(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if !d.NewValueKnown("bump_triggers") || bumpTriggered(d) {
+		return d.SetNewComputed("last_bumped_at")
+	}
+	return nil
+}
+
+// flattenListingAction returns the `action` of the listing, which is only
+// reconciled when it is set, since it is not returned by the API. A published
+// listing whose action is `draft` or `unpublish` has been published on Forem,
+// and vice versa.
+func flattenListingAction(action string, published bool) string {
+	switch {
+	case action == "":
+		return action
+	case published && (action == string(dev.ActionDraft) || action == string(dev.ActionUnpublish)):
+		return string(dev.ActionPublish)
+	case !published && (action == string(dev.ActionPublish) || action == string(dev.ActionBump)):
+		return string(dev.ActionUnpublish)
+	}
+	return action
+}
+
+// setListingOrganization reconciles `organization_id` or, if it is used
+// instead, `organization_username` with the organization of the listing. An
+// unset `organization_id` stands for the default organization of the provider.
+func setListingOrganization(ctx context.Context, client *apiClient, d *schema.ResourceData, l *listing) error {
+	orgID := 0
+	if l.OrganizationID != nil {
+		orgID = *l.OrganizationID
+	} else if l.Organization != nil {
+		org, err := client.getOrganization(ctx, l.Organization.Username)
+		if err != nil {
+			return err
+		}
+		orgID = org.ID
+	}
+
+	if username := d.Get("organization_username").(string); username != "" {
+		switch {
+		case l.Organization == nil:
+			tflog.Debug(ctx, fmt.Sprintf("Listing with ID: %s is no longer assigned to organization: %s", d.Id(), username))
+			d.Set("organization_username", "")
+		case !strings.EqualFold(l.Organization.Username, username):
+			d.Set("organization_username", l.Organization.Username)
+		}
+		return nil
+	}
+	if d.Get("organization_id").(int) != 0 || orgID != client.defaults.OrganizationID {
+		d.Set("organization_id", orgID)
+	}
+	return nil
+}
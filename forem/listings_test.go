@@ -0,0 +1,153 @@
+package forem
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestFlattenListingExpiresAt(t *testing.T) {
+	cases := map[string]struct {
+		expiresAt string
+		want      string
+		wantError bool
+	}{
+		"empty":     {expiresAt: "", want: ""},
+		"timestamp": {expiresAt: "2022-03-01T00:00:00.000Z", want: "03/01/2022"},
+		"date":      {expiresAt: "2022-03-01", want: "03/01/2022"},
+		"schema":    {expiresAt: "03/01/2022", want: "03/01/2022"},
+		"invalid":   {expiresAt: "1st of March", wantError: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := flattenListingExpiresAt(tc.expiresAt)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("expected error: %t, got %v", tc.wantError, err)
+			}
+			if got != tc.want {
+				t.Errorf("expected `%s`, got `%s`", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFlattenListingAction(t *testing.T) {
+	cases := []struct {
+		action    string
+		published bool
+		want      string
+	}{
+		{action: "", published: true, want: ""},
+		{action: "", published: false, want: ""},
+		{action: "publish", published: true, want: "publish"},
+		{action: "bump", published: true, want: "bump"},
+		{action: "draft", published: false, want: "draft"},
+		{action: "unpublish", published: false, want: "unpublish"},
+		{action: "draft", published: true, want: "publish"},
+		{action: "unpublish", published: true, want: "publish"},
+		{action: "publish", published: false, want: "unpublish"},
+		{action: "bump", published: false, want: "unpublish"},
+	}
+	for _, tc := range cases {
+		if got := flattenListingAction(tc.action, tc.published); got != tc.want {
+			t.Errorf("expected `%s` for action `%s` and published `%t`, got `%s`", tc.want, tc.action, tc.published, got)
+		}
+	}
+}
+
+func TestParseListingExpiresAt(t *testing.T) {
+	want := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		expiresAt string
+		wantError bool
+	}{
+		"date":                {expiresAt: "03/01/2022"},
+		"timestamp":           {expiresAt: "2022-03-01T18:30:00Z"},
+		"timestamp with zone": {expiresAt: "2022-03-01T23:30:00-05:00"},
+		"invalid date":        {expiresAt: "99/99/2020", wantError: true},
+		"day first":           {expiresAt: "31/03/2022", wantError: true},
+		"iso date":            {expiresAt: "2022-03-01", wantError: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseListingExpiresAt(tc.expiresAt)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("expected error: %t, got %v", tc.wantError, err)
+			}
+			if !tc.wantError && !got.Equal(want) {
+				t.Errorf("expected %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestCheckListingExpiresAt(t *testing.T) {
+	now := time.Date(2022, 3, 1, 18, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		expiresAt time.Time
+		wantError string
+	}{
+		"today":            {expiresAt: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)},
+		"maximum lifetime": {expiresAt: time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)},
+		"yesterday":        {expiresAt: time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC), wantError: "in the past"},
+		"too far ahead":    {expiresAt: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC), wantError: "more than 30 days ahead"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkListingExpiresAt(tc.expiresAt, now)
+			if tc.wantError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+				t.Fatalf("expected error containing `%s`, got %v", tc.wantError, err)
+			}
+		})
+	}
+}
+
+func TestListingExpired(t *testing.T) {
+	now := time.Date(2022, 3, 1, 18, 0, 0, 0, time.UTC)
+	if listingExpired("", now) {
+		t.Error("expected listing without expiration date not to be expired")
+	}
+	if listingExpired("03/01/2022", now) {
+		t.Error("expected listing expiring today not to be expired")
+	}
+	if !listingExpired("02/28/2022", now) {
+		t.Error("expected listing that expired yesterday to be expired")
+	}
+}
+
+func TestResourceListingDiff_expiresAt(t *testing.T) {
+	r := resourceListing()
+	expiresAt := time.Now().AddDate(0, 0, 1)
+	state := &terraform.InstanceState{ID: "42", Attributes: map[string]string{
+		"id":            "42",
+		"title":         "listing",
+		"body_markdown": "body",
+		"body_sha256":   markdownSHA256("body"),
+		"category":      "misc",
+		"expires_at":    expiresAt.Format(listingExpiresAtLayout),
+		"on_destroy":    onDestroyUnpublish,
+	}}
+	config := map[string]interface{}{"title": "listing", "body_markdown": "body", "category": "misc", "expires_at": expiresAt.Format(time.RFC3339)}
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), &apiClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attr := diff.Attributes["expires_at"]; attr != nil && attr.Old != attr.New {
+		t.Errorf("expected the same date as a timestamp not to change `expires_at`, got %+v", attr)
+	}
+
+	config["expires_at"] = time.Now().AddDate(0, 0, -1).Format(listingExpiresAtLayout)
+	if _, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), &apiClient{}); err == nil || !strings.Contains(err.Error(), "in the past") {
+		t.Errorf("expected an error about the past date, got %v", err)
+	}
+}
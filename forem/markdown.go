@@ -0,0 +1,96 @@
+package forem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// hardLineBreak is the trailing whitespace that turns the end of a line into a
+// hard line break in Markdown.
+const hardLineBreak = "  "
+
+// normalizeMarkdown returns the canonical form of a Markdown body, the way
+// Forem stores it: LF line endings, without trailing whitespace on every line
+// and at the end of the body. Two or more trailing spaces are a hard line
+// break, so they are kept as exactly two spaces.
+func normalizeMarkdown(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	lines := strings.Split(body, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t\r")
+		if lines[i] != "" && strings.HasSuffix(strings.TrimRight(l, "\r"), hardLineBreak) {
+			lines[i] += hardLineBreak
+		}
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), " \n")
+}
+
+func normalizeMarkdownStateFunc(v interface{}) string {
+	return normalizeMarkdown(v.(string))
+}
+
+func suppressEquivalentMarkdown(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeMarkdown(old) == normalizeMarkdown(new)
+}
+
+// markdownSHA256 returns the hex encoded SHA-256 checksum of the canonical
+// form of a Markdown body.
+func markdownSHA256(body string) string {
+	sum := sha256.Sum256([]byte(normalizeMarkdown(body)))
+	return hex.EncodeToString(sum[:])
+}
+
+// getBody returns the Markdown body of an article or listing, which is read
+// from `body_file` if it is set.
+func getBody(d resourceGetter) (string, error) {
+	path := d.Get("body_file").(string)
+	if path == "" {
+		return d.Get("body_markdown").(string), nil
+	}
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("`body_file` `%s` does not exist", path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read `body_file`: %w", err)
+	}
+	return string(b), nil
+}
+
+// bodyKnown reports whether the body of an article or listing being planned
+// is known yet.
+func bodyKnown(d *schema.ResourceDiff) bool {
+	if !d.NewValueKnown("body_file") {
+		return false
+	}
+	return d.Get("body_file").(string) != "" || d.NewValueKnown("body_markdown")
+}
+
+// customizeDiffBodySHA256 plans `body_sha256` out of `body_markdown`, or out
+// of the content of `body_file`, so that changes to the file show up in the
+// plan without the body itself.
+func customizeDiffBodySHA256(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !bodyKnown(d) {
+		return d.SetNewComputed("body_sha256")
+	}
+	body, err := getBody(d)
+	if err != nil {
+		return err
+	}
+	sum := markdownSHA256(body)
+	if sum != d.Get("body_sha256").(string) {
+		return d.SetNew("body_sha256", sum)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package forem
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestNormalizeMarkdown(t *testing.T) {
+	cases := map[string]struct {
+		body string
+		want string
+	}{
+		"normalized":           {body: "# Title\n\nbody", want: "# Title\n\nbody"},
+		"windows line endings": {body: "# Title\r\n\r\nbody\r\n", want: "# Title\n\nbody"},
+		"trailing spaces":      {body: "# Title \n\nbody\t\n", want: "# Title\n\nbody"},
+		"hard line break":      {body: "line   \r\nbreak\n", want: "line  \nbreak"},
+		"hard line break tab":  {body: "line\t  \nbreak", want: "line  \nbreak"},
+		"blank line spaces":    {body: "line\n   \nbody", want: "line\n\nbody"},
+		"trailing hard break":  {body: "body  \n", want: "body"},
+		"trailing newlines":    {body: "body\n\n\n", want: "body"},
+		"leading whitespace":   {body: "    code\n", want: "    code"},
+		"empty":                {body: "\r\n", want: ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := normalizeMarkdown(tc.body); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSuppressEquivalentMarkdown(t *testing.T) {
+	if !suppressEquivalentMarkdown("body_markdown", "# Title\n\nbody", "# Title \r\n\r\nbody\r\n", nil) {
+		t.Error("expected bodies differing in line endings and trailing whitespace to be equivalent")
+	}
+	if suppressEquivalentMarkdown("body_markdown", "# Title\n\nbody", "# Title\nbody", nil) {
+		t.Error("expected bodies differing in content not to be equivalent")
+	}
+	if suppressEquivalentMarkdown("body_markdown", "line\nbreak", "line  \nbreak", nil) {
+		t.Error("expected bodies differing in hard line breaks not to be equivalent")
+	}
+}
+
+func TestMarkdownSHA256(t *testing.T) {
+	if markdownSHA256("body\r\n") != markdownSHA256("body") {
+		t.Error("expected checksums of equivalent bodies to be equal")
+	}
+	if got, want := markdownSHA256("body"), "230d8358dc8e8890b4c58deeb62912ee2f20357ae92a5cc861b98e68fe31acb5"; got != want {
+		t.Errorf("expected checksum `%s`, got `%s`", want, got)
+	}
+}
+
+func TestGetBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "post.md")
+	if err := ioutil.WriteFile(path, []byte("# From file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		raw       map[string]interface{}
+		want      string
+		wantError string
+	}{
+		"body_markdown": {
+			raw:  map[string]interface{}{"body_markdown": "# Inline"},
+			want: "# Inline",
+		},
+		"body_file": {
+			raw:  map[string]interface{}{"body_file": path},
+			want: "# From file\n",
+		},
+		"missing body_file": {
+			raw:       map[string]interface{}{"body_file": path + ".missing"},
+			wantError: "does not exist",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceListing().Schema, tc.raw)
+			got, err := getBody(d)
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Fatalf("expected error containing `%s`, got %v", tc.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCustomizeDiffBodySHA256_bodyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "post.md")
+	if err := ioutil.WriteFile(path, []byte("new body\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := resourceArticle()
+	state := &terraform.InstanceState{ID: "42", Attributes: map[string]string{
+		"id":           "42",
+		"title":        "article",
+		"body_file":    path,
+		"body_sha256":  markdownSHA256("old body"),
+		"front_matter": frontMatterMerge,
+		"on_destroy":   onDestroyUnpublish,
+	}}
+	config := map[string]interface{}{"title": "article", "body_file": path}
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), &apiClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := diff.Attributes["body_sha256"]; got == nil || got.New != markdownSHA256("new body") {
+		t.Errorf("expected the checksum of the file to be planned, got %+v", got)
+	}
+	if got, ok := diff.Attributes["body_markdown"]; ok {
+		t.Errorf("expected no body in the plan, got %+v", got)
+	}
+
+	config["body_file"] = path + ".missing"
+	if _, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), &apiClient{}); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected an error about the missing file, got %v", err)
+	}
+}
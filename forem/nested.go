@@ -0,0 +1,252 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	dev "github.com/karvounis/dev-client-go"
+)
+
+// userCache holds the IDs of the users that have been looked up by username
+// during a Terraform run.
+type userCache struct {
+	mu         sync.Mutex
+	byUsername map[string]int
+}
+
+func newUserCache() *userCache {
+	return &userCache{byUsername: map[string]int{}}
+}
+
+// getUserID returns the ID of the user. The user objects embedded in articles
+// and listings carry a `user_id` instead of an `id`, which is not decoded by
+// the client, so the ID is looked up by the username of the user.
+func (c *apiClient) getUserID(ctx context.Context, u *dev.User) (int, error) {
+	if u.ID != 0 {
+		return int(u.ID), nil
+	}
+	authUser, err := c.authenticatedUser(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if authUser.Username == u.Username {
+		return int(authUser.ID), nil
+	}
+
+	c.users.mu.Lock()
+	defer c.users.mu.Unlock()
+
+	if id, ok := c.users.byUsername[u.Username]; ok {
+		return id, nil
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Getting user with username: %s", u.Username))
+	user, err := c.GetUserByUsername(dev.UserQueryParams{URL: u.Username})
+	if err != nil {
+		return 0, err
+	}
+	c.users.byUsername[u.Username] = int(user.ID)
+	return int(user.ID), nil
+}
+
+func userSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Description: description,
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user_id": {
+					Description: "ID of the user.",
+					Type:        schema.TypeInt,
+					Computed:    true,
+				},
+				"name": {
+					Description: "Name of the user.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"username": {
+					Description: "Username of the user.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"twitter_username": {
+					Description: "User's twitter username.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"github_username": {
+					Description: "User's github username.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"website_url": {
+					Description: "User's website URL.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"profile_image": {
+					Description: "Profile image (320x320).",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func organizationSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Description: description,
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Description: "ID of the organization.",
+					Type:        schema.TypeInt,
+					Computed:    true,
+				},
+				"name": {
+					Description: "Name of the organization.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"username": {
+					Description: "Username of the organization.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"slug": {
+					Description: "Slug of the organization.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"profile_image": {
+					Description: "Profile image (640x640).",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"profile_image_90": {
+					Description: "Profile image (90x90).",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func flareTagSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Description: description,
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Description: "Name of the tag.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"bg_color_hex": {
+					Description: "Background color of the tag (hexadecimal).",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"text_color_hex": {
+					Description: "Text color of the tag (hexadecimal).",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func flattenUser(ctx context.Context, client *apiClient, u *dev.User) ([]interface{}, error) {
+	if u == nil {
+		return []interface{}{}, nil
+	}
+	id, err := client.getUserID(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{map[string]interface{}{
+		"user_id":          id,
+		"name":             u.Name,
+		"username":         u.Username,
+		"twitter_username": u.TwitterUsername,
+		"github_username":  u.GithubUsername,
+		"website_url":      u.WebsiteURL,
+		"profile_image":    u.ProfileImage,
+	}}, nil
+}
+
+func flattenOrganization(ctx context.Context, client *apiClient, o *dev.Organization) ([]interface{}, error) {
+	if o == nil {
+		return []interface{}{}, nil
+	}
+	org, err := client.getOrganization(ctx, o.Username)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{map[string]interface{}{
+		"id":               org.ID,
+		"name":             o.Name,
+		"username":         o.Username,
+		"slug":             o.Slug,
+		"profile_image":    o.ProfileImage,
+		"profile_image_90": o.ProfileImage90,
+	}}, nil
+}
+
+func flattenFlareTag(t *dev.ArticleFlareTag) []interface{} {
+	if t == nil {
+		return []interface{}{}
+	}
+	return []interface{}{map[string]interface{}{
+		"name":           t.Name,
+		"bg_color_hex":   t.BGColorHEX,
+		"text_color_hex": t.TextColorHEX,
+	}}
+}
+
+// stringMapsStateUpgraderV0 upgrades the state of r from schema version `0`,
+// where the given attributes were maps of strings, to the nested list blocks
+// of version `1`.
+func stringMapsStateUpgraderV0(r *schema.Resource, keys ...string) schema.StateUpgrader {
+	v0 := &schema.Resource{Schema: map[string]*schema.Schema{}}
+	for k, s := range r.Schema {
+		v0.Schema[k] = s
+	}
+	for _, k := range keys {
+		v0.Schema[k] = &schema.Schema{
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		}
+	}
+
+	return schema.StateUpgrader{
+		Version: 0,
+		Type:    v0.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			if rawState == nil {
+				return rawState, nil
+			}
+			for _, k := range keys {
+				m, ok := rawState[k].(map[string]interface{})
+				if !ok || len(m) == 0 {
+					rawState[k] = []interface{}{}
+					continue
+				}
+				rawState[k] = []interface{}{m}
+			}
+			return rawState, nil
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	dev "github.com/karvounis/dev-client-go"
+)
+
+func TestStringMapsStateUpgraderV0(t *testing.T) {
+	upgrader := stringMapsStateUpgraderV0(resourceArticle(), "user", "organization", "flare_tag")
+
+	rawState := map[string]interface{}{
+		"id":           "1",
+		"user":         map[string]interface{}{"username": "forem", "name": "Forem"},
+		"organization": map[string]interface{}{},
+	}
+	got, err := upgrader.Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]interface{}{
+		"id":           "1",
+		"user":         []interface{}{map[string]interface{}{"username": "forem", "name": "Forem"}},
+		"organization": []interface{}{},
+		"flare_tag":    []interface{}{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFlattenUser(t *testing.T) {
+	requests := map[string]int{}
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/users/me":
+			fmt.Fprint(w, `{"id":1,"username":"forem"}`)
+		case "/users/by_username":
+			fmt.Fprintf(w, `{"id":2,"username":%q}`, r.URL.Query().Get("url"))
+		default:
+			writeDevError(w, http.StatusNotFound)
+		}
+	}, 0)
+
+	cases := map[string]int{"forem": 1, "someone": 2}
+	for username, wantID := range cases {
+		for i := 0; i < 2; i++ {
+			user, err := flattenUser(context.Background(), c, &dev.User{Username: username})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if id := user[0].(map[string]interface{})["user_id"]; id != wantID {
+				t.Errorf("expected user_id %d for `%s`, got %v", wantID, username, id)
+			}
+		}
+	}
+	if requests["/users/me"] != 1 || requests["/users/by_username"] != 1 {
+		t.Errorf("expected user lookups to be cached, got %v", requests)
+	}
+
+	if user, err := flattenUser(context.Background(), c, nil); err != nil || len(user) != 0 {
+		t.Errorf("expected no user, got %v, %v", user, err)
+	}
+}
+
+func TestFlattenOrganization(t *testing.T) {
+	c := newTestOrganizationClient(t, map[string]int{})
+
+	org, err := flattenOrganization(context.Background(), c, &dev.Organization{Username: "acme", Name: "Acme"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	m := org[0].(map[string]interface{})
+	if m["id"] != 42 || m["name"] != "Acme" {
+		t.Errorf("expected organization 42 named Acme, got %v", m)
+	}
+}
@@ -0,0 +1,166 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	dev "github.com/karvounis/dev-client-go"
+)
+
+// readOrganizationUsersPerPage is the maximum page size allowed by the API.
+const readOrganizationUsersPerPage = 1000
+
+// organization is a dev.Organization together with its ID, which is returned
+// by the API but not decoded by the client.
+type organization struct {
+	dev.Organization
+	ID int `json:"id"`
+}
+
+// organizationCache holds the organizations and the membership of the
+// authenticated user that have been looked up during a Terraform run.
+// Organizations are keyed by the ID or username they have been looked up by.
+type organizationCache struct {
+	mu      sync.Mutex
+	byKey   map[string]*organization
+	members map[int]bool
+}
+
+func newOrganizationCache() *organizationCache {
+	return &organizationCache{
+		byKey:   map[string]*organization{},
+		members: map[int]bool{},
+	}
+}
+
+// getOrganization returns the organization by its ID or username, both of
+// which are accepted by the organizations endpoint. dev.Client.GetOrganization
+// is not used, since it does not return the ID of the organization.
+func (c *apiClient) getOrganization(ctx context.Context, key string) (*organization, error) {
+	c.organizations.mu.Lock()
+	defer c.organizations.mu.Unlock()
+
+	if org, ok := c.organizations.byKey[key]; ok {
+		return org, nil
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Getting organization: %s", key))
+	req, err := c.NewRequest(ctx, http.MethodGet, "/organizations/"+url.PathEscape(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	org := new(organization)
+	if err := c.SendHttpRequest(req, org); err != nil {
+		return nil, err
+	}
+
+	c.organizations.byKey[key] = org
+	return org, nil
+}
+
+// isOrganizationMember reports whether the authenticated user is a member of
+// the organization.
+func (c *apiClient) isOrganizationMember(ctx context.Context, org *organization) (bool, error) {
+	user, err := c.authenticatedUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	c.organizations.mu.Lock()
+	defer c.organizations.mu.Unlock()
+
+	if member, ok := c.organizations.members[org.ID]; ok {
+		return member, nil
+	}
+
+	member := false
+	for page := int32(1); !member; page++ {
+		tflog.Debug(ctx, fmt.Sprintf("Getting users of organization: %s with page: %d", org.Username, page))
+		users, err := c.GetOrganizationUsers(org.Username, dev.OrganizationQueryParams{Page: page, PerPage: readOrganizationUsersPerPage})
+		if err != nil {
+			return false, err
+		}
+		for _, u := range users {
+			if u.ID == user.ID {
+				member = true
+				break
+			}
+		}
+		if len(users) < readOrganizationUsersPerPage {
+			break
+		}
+	}
+
+	c.organizations.members[org.ID] = member
+	return member, nil
+}
+
+// resolveOrganizationUsername returns the ID of the organization set by the
+// `organization_username` of a resource, or `0` if it is not set.
+func resolveOrganizationUsername(ctx context.Context, client *apiClient, d *schema.ResourceData) (int, error) {
+	v, ok := d.GetOk("organization_username")
+	if !ok {
+		return 0, nil
+	}
+	org, err := client.getOrganization(ctx, v.(string))
+	if isNotFound(err) {
+		return 0, fmt.Errorf("organization `%s` not found", v.(string))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return org.ID, nil
+}
+
+// validateOrganizationMembership makes sure that the organization, looked up
+// by its ID or username, exists and that the authenticated user is a member
+// of it. Otherwise, the API would silently drop the assignment of the article
+// or listing to it.
+func validateOrganizationMembership(ctx context.Context, client *apiClient, key string) error {
+	org, err := client.getOrganization(ctx, key)
+	if isNotFound(err) {
+		return fmt.Errorf("organization `%s` not found", key)
+	}
+	if err != nil {
+		return err
+	}
+
+	member, err := client.isOrganizationMember(ctx, org)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return fmt.Errorf("the authenticated user is not a member of organization `%s` with ID `%d`", org.Username, org.ID)
+	}
+	return nil
+}
+
+// customizeDiffOrganization validates the organization of a resource at plan
+// time, whenever it is created or its organization changes.
+func customizeDiffOrganization(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("organization_id") || !d.NewValueKnown("organization_username") {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChange("organization_id") && !d.HasChange("organization_username") {
+		return nil
+	}
+
+	client := meta.(*apiClient)
+	if username := d.Get("organization_username").(string); username != "" {
+		return validateOrganizationMembership(ctx, client, username)
+	}
+	id := d.Get("organization_id").(int)
+	if id == 0 {
+		id = client.defaults.OrganizationID
+	}
+	if id == 0 {
+		return nil
+	}
+	return validateOrganizationMembership(ctx, client, strconv.Itoa(id))
+}
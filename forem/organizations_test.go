@@ -0,0 +1,104 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func newTestOrganizationClient(t *testing.T, requests map[string]int) *apiClient {
+	t.Helper()
+
+	return newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/users/me":
+			fmt.Fprint(w, `{"id":1,"username":"forem"}`)
+		case "/organizations/42", "/organizations/acme":
+			fmt.Fprint(w, `{"id":42,"username":"acme"}`)
+		case "/organizations/43":
+			fmt.Fprint(w, `{"id":43,"username":"other"}`)
+		case "/organizations/acme/users":
+			fmt.Fprint(w, `[{"id":2,"username":"someone"},{"id":1,"username":"forem"}]`)
+		case "/organizations/other/users":
+			fmt.Fprint(w, `[{"id":2,"username":"someone"}]`)
+		default:
+			writeDevError(w, http.StatusNotFound)
+		}
+	}, 0)
+}
+
+func TestValidateOrganizationMembership(t *testing.T) {
+	cases := map[string]struct {
+		key       string
+		wantError string
+	}{
+		"member":             {key: "42"},
+		"member by username": {key: "acme"},
+		"not a member":       {key: "43", wantError: "not a member of organization `other`"},
+		"not found":          {key: "44", wantError: "organization `44` not found"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestOrganizationClient(t, map[string]int{})
+
+			err := validateOrganizationMembership(context.Background(), c, tc.key)
+			if tc.wantError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+				t.Fatalf("expected error containing `%s`, got %v", tc.wantError, err)
+			}
+		})
+	}
+}
+
+func TestValidateOrganizationMembership_cached(t *testing.T) {
+	requests := map[string]int{}
+	c := newTestOrganizationClient(t, requests)
+
+	for i := 0; i < 3; i++ {
+		if err := validateOrganizationMembership(context.Background(), c, "42"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	for path, n := range requests {
+		if n != 1 {
+			t.Errorf("expected a single request to `%s`, got %d", path, n)
+		}
+	}
+}
+
+func TestBuildArticleBodySchema_organizationUsername(t *testing.T) {
+	c := newTestOrganizationClient(t, map[string]int{})
+
+	d := schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":                 "My Article",
+		"body_markdown":         "body",
+		"organization_username": "acme",
+	})
+	abc, err := buildArticleBodySchema(context.Background(), c, d)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if abc.Article.OrganizationID != 42 {
+		t.Errorf("expected organization ID 42, got %d", abc.Article.OrganizationID)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+		"title":                 "listing",
+		"body_markdown":         "body",
+		"category":              "cfp",
+		"organization_username": "unknown",
+	})
+	if _, err := buildListingBodySchema(context.Background(), c, d); err == nil || !strings.Contains(err.Error(), "organization `unknown` not found") {
+		t.Errorf("expected organization not found error, got %v", err)
+	}
+}
@@ -3,18 +3,23 @@ package forem
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	dev "github.com/karvounis/dev-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
 	devToBaseURL = "https://dev.to/api"
 
-	envForemAPIKey = "FOREM_API_KEY"
-	envForemHost   = "FOREM_HOST"
+	envForemAPIKey          = "FOREM_API_KEY"
+	envForemHost            = "FOREM_HOST"
+	envForemProfile         = "FOREM_PROFILE"
+	envForemCredentialsFile = "FOREM_CREDENTIALS_FILE"
 )
 
 func init() {
@@ -52,10 +57,38 @@ func Provider() *schema.Provider {
 		ConfigureContextFunc: providerConfigure,
 		Schema: map[string]*schema.Schema{
 			"api_key": {
-				Description: fmt.Sprintf("API key to be able to communicate with the FOREM API. Environment variable: `%s`.", envForemAPIKey),
+				Description:   fmt.Sprintf("API key to be able to communicate with the FOREM API. Environment variable: `%s`.", envForemAPIKey),
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"api_key_file", "credentials_command", "profile"},
+			},
+			"api_key_file": {
+				Description:   "Path to a file that contains the API key.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"api_key", "credentials_command", "profile"},
+			},
+			"credentials_command": {
+				Description:   "Command, and its arguments, that prints the API key to stdout, e.g. `[\"vault\", \"kv\", \"get\", \"-field=api_key\", \"secret/forem\"]`.",
+				Type:          schema.TypeList,
+				Optional:      true,
+				MinItems:      1,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"api_key", "api_key_file", "profile"},
+			},
+			"profile": {
+				Description:   fmt.Sprintf("Name of the profile in `credentials_file` to read the API key from. Environment variable: `%s`.", envForemProfile),
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc(envForemProfile, nil),
+				ConflictsWith: []string{"api_key", "api_key_file", "credentials_command"},
+			},
+			"credentials_file": {
+				Description: fmt.Sprintf("Path to the credentials file that contains the profiles. Environment variable: `%s`. Defaults to: `%s`.", envForemCredentialsFile, defaultCredentialsFile),
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc(envForemAPIKey, nil),
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(envForemCredentialsFile, defaultCredentialsFile),
 			},
 			"host": {
 				Description: fmt.Sprintf("Host of the FOREM API. Environment variable: `%s`. Defaults to: `%s`.", envForemHost, devToBaseURL),
@@ -63,6 +96,149 @@ func Provider() *schema.Provider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc(envForemHost, devToBaseURL),
 			},
+			"defaults": {
+				Description: "Default values that are merged into every `forem_article` and `forem_listing`. Values set on a resource take precedence. " +
+					"Only the default tags are part of the plan, so changing the other defaults does not update existing articles.",
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"organization_id": {
+							Description: "ID of the organization that articles and listings are assigned to, unless they set `organization_id` themselves. " +
+								"Articles are assigned to it when they are created, listings whenever they are created or updated.",
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"tags": {
+							Description: "Tags that are added to the tags of every article and listing. The merged tags are exposed by the `tags_all` attribute of the resources.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        tagElemSchema(),
+						},
+						"series": {
+							Description: "Series of articles that do not set `series` themselves. It is applied when an article is created or its `series` changes.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"canonical_url_prefix": {
+							Description: "Prefix of the canonical URL of articles that do not set `canonical_url` themselves. " +
+								"The URL is completed with the slug of the title of the article when the article is created, so changing the prefix or the title later does not change it.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+						},
+					},
+				},
+			},
+			"verify_credentials": {
+				Description: "Verify the API key and the host when the provider is configured, by fetching the authenticated user.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"request_timeout": {
+				Description:  "Timeout of each attempt of a request to the API in seconds. Retries and the waits between them are not counted against it. Set to `0` to disable the timeout.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultReqTimeout,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"proxy_url": {
+				Description:  "URL of the proxy to reach the API through. When not set, the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables are used.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+			},
+			"ca_cert_file": {
+				Description:   "Path to a PEM encoded CA certificate to trust, in addition to the system ones, e.g. the private CA of a self-hosted Forem instance.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_pem"},
+			},
+			"ca_cert_pem": {
+				Description:   "PEM encoded CA certificate to trust, in addition to the system ones.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_file"},
+			},
+			"client_cert_file": {
+				Description:   "Path to a PEM encoded client certificate for mutual TLS.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				RequiredWith:  []string{"client_key_file"},
+				ConflictsWith: []string{"client_cert_pem"},
+			},
+			"client_key_file": {
+				Description:   "Path to the PEM encoded private key of `client_cert_file`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				RequiredWith:  []string{"client_cert_file"},
+				ConflictsWith: []string{"client_key_pem"},
+			},
+			"client_cert_pem": {
+				Description:   "PEM encoded client certificate for mutual TLS.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				RequiredWith:  []string{"client_key_pem"},
+				ConflictsWith: []string{"client_cert_file"},
+			},
+			"client_key_pem": {
+				Description:   "PEM encoded private key of `client_cert_pem`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				RequiredWith:  []string{"client_cert_pem"},
+				ConflictsWith: []string{"client_key_file"},
+			},
+			"insecure_skip_verify": {
+				Description: "Skip the verification of the TLS certificate of the host. Only meant for testing.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"max_retries": {
+				Description:  "Maximum number of times a request is retried when the API responds with `429` or a `5xx` status code. Requests that create resources are only retried on `429`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_max_wait": {
+				Description:  "Maximum number of seconds to wait between retries. Caps both the exponential backoff and the `Retry-After` header returned by the API.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultRetryMaxWait,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"requests_per_second": {
+				Description:  "Maximum number of requests per second sent to the API by all resources and data sources combined. Set to `0` to disable the limit.",
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      defaultRequestsPerSecond,
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"burst": {
+				Description:  "Maximum number of requests that can be sent at once before `requests_per_second` kicks in.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultBurst,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"write_requests_per_second": {
+				Description:  "Maximum number of requests per second that create or update resources, e.g. articles and listings. Applies on top of `requests_per_second`. Set to `0` to disable the limit.",
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      defaultWriteRequestsPerSecond,
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"write_burst": {
+				Description:  "Maximum number of write requests that can be sent at once before `write_requests_per_second` kicks in.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultWriteBurst,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"forem_article": resourceArticle(),
@@ -78,18 +254,89 @@ func Provider() *schema.Provider {
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	apiKey := d.Get("api_key").(string)
+	apiKey, diags := resolveAPIKey(ctx, d)
+	if diags.HasError() {
+		return nil, diags
+	}
 	host := d.Get("host").(string)
 
-	var diags diag.Diagnostics
-	c, err := dev.NewClient(dev.Options{Token: apiKey, Host: host})
+	transport, diags := getHTTPTransportOptions(d)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	c, err := newAPIClient(apiClientOptions{
+		Host:         host,
+		Token:        apiKey,
+		MaxRetries:   d.Get("max_retries").(int),
+		RetryMaxWait: time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+
+		RequestTimeout: time.Duration(d.Get("request_timeout").(int)) * time.Second,
+		Transport:      transport,
+
+		RequestsPerSecond:      d.Get("requests_per_second").(float64),
+		Burst:                  d.Get("burst").(int),
+		WriteRequestsPerSecond: d.Get("write_requests_per_second").(float64),
+		WriteBurst:             d.Get("write_burst").(int),
+	})
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Unable to create Forem client",
-			Detail:   fmt.Sprintf("Unable to use apiKey for host `%s`", host),
+			Detail:   fmt.Sprintf("Unable to create client for host `%s`: %s", host, err),
 		})
 		return nil, diags
 	}
+
+	c.defaults = getProviderDefaults(d)
+
+	if d.Get("verify_credentials").(bool) {
+		tflog.Debug(ctx, fmt.Sprintf("Verifying credentials for host: %s", host))
+		user, verifyDiags := c.verifyCredentials(ctx)
+		if verifyDiags.HasError() {
+			return nil, append(diags, verifyDiags...)
+		}
+		tflog.Debug(ctx, fmt.Sprintf("Authenticated as user: %s", user.Username))
+		c.user = user
+	}
 	return c, diags
 }
+
+func getHTTPTransportOptions(d *schema.ResourceData) (httpTransportOptions, diag.Diagnostics) {
+	opts := httpTransportOptions{
+		ProxyURL:           d.Get("proxy_url").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+	}
+
+	var err error
+	if opts.CACertPEM, err = getPEM(d, "ca_cert_file", "ca_cert_pem"); err != nil {
+		return opts, diag.FromErr(err)
+	}
+	if opts.ClientCertPEM, err = getPEM(d, "client_cert_file", "client_cert_pem"); err != nil {
+		return opts, diag.FromErr(err)
+	}
+	if opts.ClientKeyPEM, err = getPEM(d, "client_key_file", "client_key_pem"); err != nil {
+		return opts, diag.FromErr(err)
+	}
+	return opts, nil
+}
+
+// getPEM returns the PEM content of either the file or the inline argument.
+func getPEM(d *schema.ResourceData, fileKey, pemKey string) ([]byte, error) {
+	if v, ok := d.GetOk(pemKey); ok {
+		return []byte(v.(string)), nil
+	}
+	v, ok := d.GetOk(fileKey)
+	if !ok {
+		return nil, nil
+	}
+	path, err := expandHome(v.(string))
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read `%s`: %w", fileKey, err)
+	}
+	return b, nil
+}
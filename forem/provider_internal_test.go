@@ -0,0 +1,114 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestProviderConfigure_verifyCredentials(t *testing.T) {
+	cases := map[string]struct {
+		handler     http.HandlerFunc
+		wantSummary string
+	}{
+		"valid": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/users/me" || r.Header.Get("api-key") != "test" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				fmt.Fprint(w, `{"id":1,"username":"forem"}`)
+			},
+		},
+		"invalid api key": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeDevError(w, http.StatusUnauthorized)
+			},
+			wantSummary: "Invalid Forem API key",
+		},
+		"not a forem api": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<html>Not Found</html>`)
+			},
+			wantSummary: "Invalid Forem host",
+		},
+		"rate limited": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeDevError(w, http.StatusTooManyRequests)
+			},
+			wantSummary: "Unable to verify Forem credentials",
+		},
+		"server error": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+				fmt.Fprint(w, `<html>Bad Gateway</html>`)
+			},
+			wantSummary: "Unable to verify Forem credentials",
+		},
+		"not a forem user": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `<html>Welcome</html>`)
+			},
+			wantSummary: "Invalid Forem host",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+				"api_key":     "test",
+				"host":        srv.URL,
+				"max_retries": 0,
+			})
+			meta, diags := providerConfigure(context.Background(), d)
+			if tc.wantSummary != "" {
+				if len(diags) != 1 || diags[0].Summary != tc.wantSummary {
+					t.Fatalf("expected `%s`, got %v", tc.wantSummary, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if user := meta.(*apiClient).user; user == nil || user.Username != "forem" {
+				t.Errorf("expected authenticated user `forem`, got %+v", user)
+			}
+		})
+	}
+}
+
+func TestProviderConfigure_unreachableHost(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"api_key":     "test",
+		"host":        srv.URL,
+		"max_retries": 0,
+	})
+	_, diags := providerConfigure(context.Background(), d)
+	if len(diags) != 1 || diags[0].Summary != "Unable to verify Forem credentials" {
+		t.Fatalf("expected unreachable host error, got %v", diags)
+	}
+}
+
+func TestProviderConfigure_skipVerification(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"api_key":            "test",
+		"host":               "http://127.0.0.1:0",
+		"verify_credentials": false,
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if meta.(*apiClient).user != nil {
+		t.Error("expected no authenticated user without verification")
+	}
+}
@@ -0,0 +1,91 @@
+package forem
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// publishPending reports whether an article scheduled with `publish_after` is
+// still waiting to be published at the given time.
+func publishPending(publishAfter string, now time.Time) (bool, error) {
+	if publishAfter == "" {
+		return false, nil
+	}
+	t, err := time.Parse(time.RFC3339, publishAfter)
+	if err != nil {
+		return false, err
+	}
+	return now.Before(t), nil
+}
+
+// customizeDiffPublishAfter plans `published` and `publish_pending` out of
+// `publish_after`, so that the plan shows whether the article is about to be
+// published.
+func customizeDiffPublishAfter(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("publish_after") {
+		if err := d.SetNewComputed("published"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("publish_pending")
+	}
+
+	publishAfter := d.Get("publish_after").(string)
+	if publishAfter == "" {
+		old, _ := d.GetChange("publish_after")
+		wasPending, err := publishPending(old.(string), time.Now())
+		if err != nil {
+			return err
+		}
+		configured := publishedConfigured(d)
+		if err := checkPublishAfterRemoved(wasPending, configured); err != nil {
+			return err
+		}
+		// `published` used to default to `false`, which still applies when
+		// it is left out of the configuration.
+		if !configured {
+			if err := setNewIfChanged(d, "published", false); err != nil {
+				return err
+			}
+		}
+		return setNewIfChanged(d, "publish_pending", false)
+	}
+
+	pending, err := publishPending(publishAfter, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := setNewIfChanged(d, "published", !pending); err != nil {
+		return err
+	}
+	return setNewIfChanged(d, "publish_pending", pending)
+}
+
+// checkPublishAfterRemoved makes removing `publish_after` from an article that
+// is still waiting to be published an explicit choice, since leaving
+// `published` out would silently cancel the scheduled publication.
+func checkPublishAfterRemoved(pending, publishedConfigured bool) error {
+	if pending && !publishedConfigured {
+		return errors.New("`publish_after` has been removed while the article is waiting to be published: set `published` to `true` to publish it now, or to `false` to cancel the scheduled publication")
+	}
+	return nil
+}
+
+// publishedConfigured reports whether `published` is set in the
+// configuration. The raw configuration is not available outside of a
+// Terraform run, in which case the planned value is kept as it is.
+func publishedConfigured(d *schema.ResourceDiff) bool {
+	config := d.GetRawConfig()
+	return config.IsNull() || !config.GetAttr("published").IsNull()
+}
+
+// setNewIfChanged plans the value of a computed key, unless it is planned
+// already.
+func setNewIfChanged(d *schema.ResourceDiff, key string, value interface{}) error {
+	if d.NewValueKnown(key) && d.Get(key) == value {
+		return nil
+	}
+	return d.SetNew(key, value)
+}
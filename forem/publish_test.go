@@ -0,0 +1,136 @@
+package forem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestPublishPending(t *testing.T) {
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		publishAfter string
+		want         bool
+		wantError    bool
+	}{
+		"not scheduled":       {publishAfter: ""},
+		"past":                {publishAfter: "2022-03-01T09:59:59Z"},
+		"now":                 {publishAfter: "2022-03-01T10:00:00Z"},
+		"future":              {publishAfter: "2022-03-01T10:00:01Z", want: true},
+		"future in time zone": {publishAfter: "2022-03-01T11:30:00+01:00", want: true},
+		"invalid":             {publishAfter: "01/03/2022", wantError: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := publishPending(tc.publishAfter, now)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("expected error: %t, got %v", tc.wantError, err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCustomizeDiffPublishAfter(t *testing.T) {
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	cases := map[string]struct {
+		state         map[string]string
+		publishAfter  string
+		wantPublished string
+		wantPending   string
+	}{
+		"create scheduled": {
+			publishAfter:  future,
+			wantPublished: "false",
+			wantPending:   "true",
+		},
+		"pending": {
+			state:         map[string]string{"published": "false", "publish_pending": "true", "publish_after": future},
+			publishAfter:  future,
+			wantPublished: "",
+			wantPending:   "",
+		},
+		"due": {
+			state:         map[string]string{"published": "false", "publish_pending": "true", "publish_after": past},
+			publishAfter:  past,
+			wantPublished: "true",
+			wantPending:   "false",
+		},
+		"unscheduled while pending": {
+			state:         map[string]string{"published": "false", "publish_pending": "true", "publish_after": future},
+			publishAfter:  "",
+			wantPublished: "",
+			wantPending:   "false",
+		},
+		"rescheduled": {
+			state:         map[string]string{"published": "true", "publish_pending": "false", "publish_after": past},
+			publishAfter:  future,
+			wantPublished: "false",
+			wantPending:   "true",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &terraform.InstanceState{Attributes: map[string]string{}}
+			if tc.state != nil {
+				s.ID = "42"
+				s.Attributes = map[string]string{
+					"id":            "42",
+					"title":         "article",
+					"body_markdown": "body",
+					"body_sha256":   markdownSHA256("body"),
+					"front_matter":  frontMatterMerge,
+					"on_destroy":    onDestroyUnpublish,
+				}
+				for k, v := range tc.state {
+					s.Attributes[k] = v
+				}
+			}
+			config := map[string]interface{}{"title": "article", "body_markdown": "body", "publish_after": tc.publishAfter}
+
+			diff, err := resourceArticle().Diff(context.Background(), s, terraform.NewResourceConfigRaw(config), &apiClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for k, want := range map[string]string{"published": tc.wantPublished, "publish_pending": tc.wantPending} {
+				attr := diff.Attributes[k]
+				if want == "" {
+					if attr != nil && attr.Old != attr.New {
+						t.Errorf("expected no change of `%s`, got %+v", k, attr)
+					}
+					continue
+				}
+				if attr == nil || attr.New != want {
+					t.Errorf("expected `%s` to be planned as `%s`, got %+v", k, want, attr)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPublishAfterRemoved(t *testing.T) {
+	cases := map[string]struct {
+		pending             bool
+		publishedConfigured bool
+		wantError           bool
+	}{
+		"not pending":               {},
+		"pending with published":    {pending: true, publishedConfigured: true},
+		"pending without published": {pending: true, wantError: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkPublishAfterRemoved(tc.pending, tc.publishedConfigured)
+			if (err != nil) != tc.wantError {
+				t.Errorf("expected error: %t, got %v", tc.wantError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package forem
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestsPerSecond      = 10
+	defaultBurst                  = 10
+	defaultWriteRequestsPerSecond = 1
+	defaultWriteBurst             = 1
+)
+
+// tokenBucket is a minimal token bucket limiter. Tokens are refilled at
+// rate per second up to burst. A request that finds the bucket empty
+// reserves a token in advance and waits until it becomes available, so
+// concurrent callers are served in the order they arrived.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns nil, i.e. no limit, when rate is not positive.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	wait := b.reserve()
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitTransport is an http.RoundTripper that paces every request through
+// a shared bucket and additionally paces requests that modify data through a
+// stricter writes bucket.
+type rateLimitTransport struct {
+	next   http.RoundTripper
+	all    *tokenBucket
+	writes *tokenBucket
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReadOnly(req.Method) {
+		if err := t.writes.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.all.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+func isReadOnly(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package forem
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_paceAfterBurst(t *testing.T) {
+	b := newTokenBucket(20, 2)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 2 requests fit in the burst, the other 2 have to wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected requests to be paced, finished in %s", elapsed)
+	}
+}
+
+func TestTokenBucket_disabled(t *testing.T) {
+	b := newTokenBucket(0, 1)
+	if b != nil {
+		t.Fatal("expected no limiter for a zero rate")
+	}
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTokenBucket_waitHonorsContext(t *testing.T) {
+	b := newTokenBucket(0.1, 1)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); err == nil {
+		t.Fatal("expected context error, got nil")
+	}
+}
+
+func TestRateLimitTransport_writesUseSeparateBudget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	transport := &rateLimitTransport{
+		next:   http.DefaultTransport,
+		all:    newTokenBucket(1000, 10),
+		writes: newTokenBucket(1, 1),
+	}
+	client := &http.Client{Transport: transport}
+
+	do := func(method string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, method, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := do(http.MethodGet); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	// Takes the only write token, which is refilled after a second.
+	if wait := transport.writes.reserve(); wait != 0 {
+		t.Fatalf("expected reads not to use the writes budget, write has to wait %s", wait)
+	}
+	if err := do(http.MethodPost); err == nil {
+		t.Error("expected write to be paced by the writes budget")
+	}
+	if err := do(http.MethodGet); err != nil {
+		t.Errorf("expected read not to wait for the writes budget: %s", err)
+	}
+}
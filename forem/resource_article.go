@@ -3,24 +3,35 @@ package forem
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	dev "github.com/karvounis/dev-client-go"
 )
 
 const (
-	maxArticleTags      = 4
-	readArticlesPerPage = 25
+	maxArticleTags = 4
+
+	onDestroyUnpublish = "unpublish"
+	onDestroyAbandon   = "abandon"
+	onDestroyError     = "error"
 )
 
+var allowedArticleOnDestroy = []string{onDestroyUnpublish, onDestroyAbandon, onDestroyError}
+
 func resourceArticle() *schema.Resource {
-	return &schema.Resource{
-		Description: "`forem_article` resource creates and updates a particular article." +
+	r := &schema.Resource{
+		Description: "`forem_article` resource creates and updates a particular article. " +
+			"The API does not allow deleting articles, so what happens on destroy is controlled by `on_destroy`. " +
+			"Articles can be imported by their ID, URL or `username/slug` path." +
 			"\n\n## API Docs\n\n" +
 			"- https://developers.forem.com/api#operation/createArticle\n" +
 			"- https://developers.forem.com/api#operation/updateArticle",
@@ -28,8 +39,16 @@ func resourceArticle() *schema.Resource {
 		CreateContext: resourceArticleCreate,
 		UpdateContext: resourceArticleUpdate,
 		DeleteContext: resourceArticleDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffTagsAll(maxArticleTags, frontMatterDiffDefaults),
+			customizeDiffBodySHA256,
+			customizeDiffOrganization,
+			customizeDiffPublishAfter,
+			customizeDiffFrontMatter,
+		),
+		SchemaVersion: 1,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceArticleImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -48,22 +67,112 @@ func resourceArticle() *schema.Resource {
 				Computed:    true,
 			},
 			"body_markdown": {
-				Description: "The body of the article in Markdown format.",
+				Description:      "The body of the article in Markdown format. Line endings and trailing whitespace are normalized the way Forem stores the body, keeping the two trailing spaces of hard line breaks.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"body_markdown", "body_file"},
+				StateFunc:        normalizeMarkdownStateFunc,
+				DiffSuppressFunc: suppressEquivalentMarkdown,
+			},
+			"body_file": {
+				Description:  "Path to a file with the body of the article in Markdown format, read at plan and apply time. Only its checksum is tracked in `body_sha256`, which keeps long bodies out of the plan and the state. Alternative to `body_markdown`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"body_markdown", "body_file"},
+			},
+			"body_sha256": {
+				Description: "SHA-256 checksum of the normalized `body_markdown` or content of `body_file`, which is easier to follow in the plan than the body of a long article.",
 				Type:        schema.TypeString,
-				Required:    true,
+				Computed:    true,
+			},
+			"front_matter": {
+				Description: "How to handle a Jekyll-style front matter header in the body, which Forem would otherwise apply on top of the arguments. " +
+					"`strip` removes the header from the body sent to Forem. " +
+					"`merge` removes it as well, uses its `series`, `description`, `cover_image` and `canonical_url` when the arguments are not set and its `tags` along with the arguments, " +
+					"and fails the plan if its `title`, `published` or any of the former conflict with them. " +
+					"`reject` fails the plan if there is a header.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      frontMatterMerge,
+				ValidateFunc: validation.StringInSlice(allowedFrontMatterModes, false),
+			},
+			"parsed_front_matter": {
+				Description: "Values of the front matter header of the body.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"title": {
+							Description: "Title of the article.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"published": {
+							Description: "Whether the article is published.",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+						"tags": {
+							Description: "List of tags of the article.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"series": {
+							Description: "Article series name.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"description": {
+							Description: "Article description.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"cover_image": {
+							Description: "URL of the cover image of the article.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"canonical_url": {
+							Description: "Canonical URL of the article.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
 			},
 			"tags": {
-				Description: "List of tags related to the article.",
+				Description: "List of tags related to the article. Tags may only contain letters and digits and are stored in lowercase, the way Forem does.",
 				Type:        schema.TypeList,
 				Optional:    true,
 				MaxItems:    maxArticleTags,
+				Elem:        tagElemSchema(),
+			},
+			"tags_all": {
+				Description: "List of tags of the article, including the default tags of the provider.",
+				Type:        schema.TypeList,
+				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			"published": {
-				Description: "Set to `true` to create a published article.",
+				Description:   "Set to `true` to create a published article. Defaults to `false`, unless `publish_after` is set.",
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"publish_after"},
+			},
+			"publish_after": {
+				Description: "When to publish the article, in RFC3339 format. The article is created as a draft and published on the first apply after that time. " +
+					"Changing it to a time in the future turns a published article back into a draft. Removing it before that time requires `published` to be set, to either publish the article right away or cancel its publication.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.IsRFC3339Time,
+				ConflictsWith: []string{"published"},
+			},
+			"publish_pending": {
+				Description: "Whether the article is waiting for `publish_after` to be published.",
 				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
+				Computed:    true,
 			},
 			"series": {
 				Description: "Article series name. All articles belonging to the same series need to have the same name in this parameter.",
@@ -110,9 +219,26 @@ func resourceArticle() *schema.Resource {
 				Computed:    true,
 			},
 			"organization_id": {
-				Description: "Only users belonging to an organization can assign the article to it.",
-				Type:        schema.TypeInt,
-				Optional:    true,
+				Description:   "Only users belonging to an organization can assign the article to it. The membership of the authenticated user is validated at plan time.",
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"organization_username"},
+			},
+			"organization_username": {
+				Description:   "Username of the organization the user is creating the article for, resolved to its ID by the provider. Alternative to `organization_id`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"organization_id"},
+			},
+			"on_destroy": {
+				Description: "What to do with the article on destroy. " +
+					"`unpublish` turns the article back into a draft, " +
+					"`abandon` leaves the article as it is and only removes it from the state, " +
+					"`error` refuses to destroy the article.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      onDestroyUnpublish,
+				ValidateFunc: validation.StringInSlice(allowedArticleOnDestroy, false),
 			},
 			"comments_count": {
 				Description: "Number of comments.",
@@ -139,53 +265,135 @@ func resourceArticle() *schema.Resource {
 				Type:        schema.TypeInt,
 				Computed:    true,
 			},
-			"user": {
-				Description: "User object of the article.",
-				Type:        schema.TypeMap,
-				Computed:    true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			"organization": {
-				Description: "Organization object of the article.",
-				Type:        schema.TypeMap,
-				Computed:    true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			"flare_tag": {
-				Description: "Flare tag object of the article.",
-				Type:        schema.TypeMap,
-				Computed:    true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
+			"user":         userSchema("User object of the article."),
+			"organization": organizationSchema("Organization object of the article."),
+			"flare_tag":    flareTagSchema("Flare tag object of the article."),
 			"created_at": {
-				Description: "When the listing was created.",
+				Description: "When the article was created.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
 			"updated_at": {
-				Description: "When the listing was updated.",
+				Description: "When the article was last edited, on Forem or by the provider. Falls back to `published_at` for articles that have never been edited.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"last_modified_by_provider": {
+				Description: "When the article was last created or updated by the provider, according to the local clock.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
 		},
 	}
+	r.StateUpgraders = []schema.StateUpgrader{
+		stringMapsStateUpgraderV0(r, "user", "organization", "flare_tag"),
+	}
+	return r
+}
+
+// resourceArticleImport imports an article by its ID, its URL or its
+// `username/slug` path.
+func resourceArticleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*apiClient)
+
+	id, err := resolveArticleImportID(ctx, client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(id)
+	d.Set("front_matter", frontMatterMerge)
+	d.Set("on_destroy", onDestroyUnpublish)
+	return []*schema.ResourceData{d}, nil
+}
+
+// resolveArticleImportID returns the ID of the article of the authenticated
+// user identified by an ID, a URL or a `username/slug` path. Published articles
+// are looked up by their path, drafts among the articles of the user.
+func resolveArticleImportID(ctx context.Context, client *apiClient, importID string) (string, error) {
+	importID = strings.TrimSpace(importID)
+	if _, err := strconv.Atoi(importID); err == nil {
+		return importID, nil
+	}
+
+	username, slug, err := parseArticlePath(importID)
+	if err != nil {
+		return "", err
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Getting published article with path: %s/%s", username, slug))
+	published, err := client.GetPublishedArticleByPath(url.PathEscape(username), url.PathEscape(slug))
+	if err != nil && !isNotFound(err) {
+		return "", err
+	}
+	if err == nil {
+		id := strconv.Itoa(int(published.ID))
+		_, ok, err := client.articles.Get(ctx, client, id)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", fmt.Errorf("article `%s/%s` does not belong to the authenticated user", username, slug)
+		}
+		return id, nil
+	}
+
+	article, ok, err := client.articles.GetByPath(ctx, client, "/"+username+"/"+slug)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("article `%s/%s` not found", username, slug)
+	}
+	return strconv.Itoa(int(article.ID)), nil
+}
+
+// parseArticlePath returns the username and the slug out of the URL or the
+// `username/slug` path of an article.
+func parseArticlePath(s string) (string, string, error) {
+	path := s
+	if u, err := url.Parse(s); err == nil && u.Host != "" {
+		path = u.Path
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("expected an article ID, URL or `username/slug` path, got `%s`", s)
+	}
+	return parts[0], parts[1], nil
 }
 
-// TODO: Waiting for API to allow deletion of an article
 func resourceArticleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient)
+
+	switch d.Get("on_destroy").(string) {
+	case onDestroyAbandon:
+		tflog.Debug(ctx, fmt.Sprintf("Abandoning article with ID: %s", d.Id()))
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Article abandoned",
+			Detail:   fmt.Sprintf("Article with ID `%s` has been removed from the state but still exists on Forem, since `on_destroy` is set to `%s`.", d.Id(), onDestroyAbandon),
+		}}
+	case onDestroyError:
+		return diag.Errorf("article with ID `%s` cannot be destroyed since `on_destroy` is set to `%s`", d.Id(), onDestroyError)
+	}
+
+	// Only `published` is sent, since dev.Client.UpdateArticle would clear
+	// every other field of the article that is not set on the resource.
+	tflog.Debug(ctx, fmt.Sprintf("Unpublishing article with ID: %s", d.Id()))
+	if err := client.updateArticle(ctx, d.Id(), map[string]interface{}{"published": false}); err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Unpublished article with ID: %s", d.Id()))
+	client.articles.Invalidate(d.Id())
+
 	return nil
 }
 
 func resourceArticleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
-	abc := getArticleBodySchemaFromResourceData(d)
+	abc, err := buildArticleBodySchema(ctx, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	tflog.Debug(ctx, fmt.Sprintf("Creating article with title: `%s`", abc.Article.Title))
 
 	resp, err := client.CreateArticle(abc, nil)
@@ -195,151 +403,296 @@ func resourceArticleCreate(ctx context.Context, d *schema.ResourceData, meta int
 	tflog.Debug(ctx, fmt.Sprintf("Created article ID: `%d`", resp.ID))
 
 	d.SetId(strconv.Itoa(int(resp.ID)))
-	d.Set("created_at", time.Now().Format(time.RFC3339))
+	client.articles.Invalidate(d.Id())
+	d.Set("last_modified_by_provider", time.Now().Format(time.RFC3339))
 
-	return resourceArticleRead(ctx, d, meta)
+	return readArticle(ctx, d, meta, false)
 }
 
 func resourceArticleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
-	abc := getArticleBodySchemaFromResourceData(d)
-	if !d.HasChange("canonical_url") {
-		abc.Article.CanonicalURL = ""
-	}
-	tflog.Debug(ctx, fmt.Sprintf("Updating article with ID: %s", d.Id()))
-	if _, err := client.UpdateArticle(d.Id(), abc, nil); err != nil {
+	abc, err := buildArticleBodySchema(ctx, client, d)
+	if err != nil {
 		return diag.FromErr(err)
 	}
-	tflog.Debug(ctx, fmt.Sprintf("Updated listing with ID: %s", d.Id()))
+	fields := getArticleUpdateFields(d, abc)
+	if len(fields) > 0 {
+		tflog.Debug(ctx, fmt.Sprintf("Updating article with ID: %s", d.Id()))
+		if err := client.updateArticle(ctx, d.Id(), fields); err != nil {
+			return diag.FromErr(err)
+		}
+		tflog.Debug(ctx, fmt.Sprintf("Updated article with ID: %s", d.Id()))
+		client.articles.Invalidate(d.Id())
+		// The article has just been edited, so `updated_at` is fetched again.
+		d.Set("updated_at", "")
+	}
 
-	d.Set("updated_at", time.Now().Format(time.RFC3339))
+	d.Set("last_modified_by_provider", time.Now().Format(time.RFC3339))
 
-	return resourceArticleRead(ctx, d, meta)
+	return readArticle(ctx, d, meta, false)
 }
 
 func resourceArticleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	return readArticle(ctx, d, meta, true)
+}
 
-	id := d.Get("id").(string)
-	tflog.Debug(ctx, fmt.Sprintf("Getting article with ID: %s", id))
+// readArticle sets the state of the article. An article that is missing is
+// only removed from the state on a refresh, as right after it has been
+// created or updated that would orphan it.
+func readArticle(ctx context.Context, d *schema.ResourceData, meta interface{}, removeMissing bool) diag.Diagnostics {
+	client := meta.(*apiClient)
 
-	page := int32(1)
-	perPage := int32(readArticlesPerPage)
-	missing := true
-	var article dev.Article
+	id := d.Id()
+	tflog.Debug(ctx, fmt.Sprintf("Getting article with ID: %s", id))
 
-	for missing {
-		tflog.Debug(ctx, fmt.Sprintf("Looking for article: %s with page: %d and perPage: %d", id, page, perPage))
-		articleResp, err := client.GetUserArticles(dev.ArticleQueryParams{Page: page, PerPage: perPage})
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		if len(articleResp) == 0 {
-			tflog.Debug(ctx, "No more articles!")
-			return resourceArticleCreate(ctx, d, meta)
+	article, ok, err := client.articles.Get(ctx, client, id)
+	if isNotFound(err) || (err == nil && !ok) {
+		if !removeMissing {
+			return diag.Errorf("article with ID `%s` was not found among the articles of the authenticated user after it was saved", id)
 		}
+		tflog.Warn(ctx, fmt.Sprintf("Article with ID: %s not found, removing it from state", id))
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Found article with ID: %s", id))
 
-		for _, v := range articleResp {
-			if strconv.Itoa(int(v.ID)) == id {
-				tflog.Debug(ctx, fmt.Sprintf("Found article with ID: %s", id))
-				missing = false
-				article = v
-				break
-			}
-		}
-		page++
+	// The timestamps are looked up before the state is updated, since they
+	// are fetched again when the article differs from it.
+	createdAt, updatedAt, err := getArticleTimestamps(ctx, client, article, d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(id)
 	d.Set("title", article.Title)
 	d.Set("description", article.Description)
-	d.Set("body_markdown", article.BodyMarkdown)
+	// The front matter is stripped from the body sent to Forem, so the body
+	// in the state, or in `body_file`, is kept as long as it only differs by
+	// it. A missing `body_file` is reported by the next plan.
+	body, _ := getBody(d)
+	if normalizeMarkdown(stripFrontMatter(body)) != normalizeMarkdown(article.BodyMarkdown) {
+		body = article.BodyMarkdown
+		if d.Get("body_file").(string) == "" {
+			d.Set("body_markdown", body)
+		}
+	}
+	d.Set("body_sha256", markdownSHA256(body))
+	fm, _ := getFrontMatter(d)
+	d.Set("parsed_front_matter", flattenFrontMatter(fm))
 	d.Set("slug", article.Slug)
 	d.Set("path", article.Path)
 	d.Set("url", article.URL)
 	d.Set("canonical_url", article.CanonicalURL)
-	d.Set("cover_image", article.CoverImage)
+	// A cover image taken from the front matter is left out of `cover_image`,
+	// which is not computed.
+	if coverImage := article.CoverImage; d.Get("cover_image").(string) != "" || coverImage != mergedFrontMatter(d).CoverImage {
+		d.Set("cover_image", coverImage)
+	}
 
 	d.Set("published", article.Published)
 	d.Set("published_at", article.PublishedAt)
 	d.Set("published_timestamp", article.PublishedTimestamp)
 
+	if createdAt != "" {
+		d.Set("created_at", createdAt)
+	}
+	if updatedAt != "" {
+		d.Set("updated_at", updatedAt)
+	}
+
 	d.Set("comments_count", article.CommentsCount)
 	d.Set("positive_reactions_count", article.PositiveReactionsCount)
 	d.Set("public_reactions_count", article.PublicReactionsCount)
 	d.Set("reading_time_minutes", article.ReadingTimeMinutes)
 	d.Set("page_views_count", article.PageViewsCount)
 
-	d.Set("tags", article.TagList)
-
-	if article.User != nil {
-		d.Set("user", map[string]interface{}{
-			"name":             article.User.Name,
-			"username":         article.User.Username,
-			"twitter_username": article.User.TwitterUsername,
-			"github_username":  article.User.GithubUsername,
-			"website_url":      article.User.WebsiteURL,
-			"profile_image":    article.User.ProfileImage,
-		})
-	} else {
-		d.Set("user", map[string]interface{}{})
-	}
+	d.Set("tags", withoutDefaultTags(article.TagList, expandStringList(d.Get("tags").([]interface{})), frontMatterDefaults(d, client.defaults).Tags))
+	d.Set("tags_all", article.TagList)
 
-	if article.Organization != nil {
-		d.Set("organization", map[string]interface{}{
-			"name":             article.Organization.Name,
-			"username":         article.Organization.Username,
-			"slug":             article.Organization.Slug,
-			"profile_image":    article.Organization.ProfileImage,
-			"profile_image_90": article.Organization.ProfileImage90,
-		})
-	} else {
-		d.Set("organization", map[string]interface{}{})
+	user, err := flattenUser(ctx, client, article.User)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	d.Set("user", user)
 
-	if article.FlareTag != nil {
-		d.Set("flare_tag", map[string]interface{}{
-			"name":           article.FlareTag.Name,
-			"bg_color_hex":   article.FlareTag.BGColorHEX,
-			"text_color_hex": article.FlareTag.TextColorHEX,
-		})
-	} else {
-		d.Set("flare_tag", map[string]interface{}{})
+	organization, err := flattenOrganization(ctx, client, article.Organization)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	d.Set("organization", organization)
+
+	d.Set("flare_tag", flattenFlareTag(article.FlareTag))
 
 	return nil
 }
 
-func getArticleBodySchemaFromResourceData(d *schema.ResourceData) dev.ArticleBodySchema {
+// getArticleTimestamps returns when the article was created and last edited.
+// The articles of the authenticated user do not always include them, and
+// never include when the article was last edited, in which case the ones
+// already in the state are kept as long as the article has not changed.
+// Otherwise they are taken from the published article, if there is one.
+func getArticleTimestamps(ctx context.Context, client *apiClient, article dev.Article, d resourceGetter) (string, string, error) {
+	if article.CreatedAt == "" {
+		article.CreatedAt = d.Get("created_at").(string)
+	}
+	if article.EditedAt == "" && !articleChanged(d, article) {
+		article.EditedAt = d.Get("updated_at").(string)
+	}
+	if (article.CreatedAt == "" || article.EditedAt == "") && article.Published {
+		id := strconv.Itoa(int(article.ID))
+		tflog.Debug(ctx, fmt.Sprintf("Getting published article with ID: %s", id))
+		published, err := client.GetPublishedArticleByID(id)
+		if err != nil && !isNotFound(err) {
+			return "", "", err
+		}
+		if err == nil {
+			if article.CreatedAt == "" {
+				article.CreatedAt = published.CreatedAt
+			}
+			if article.EditedAt == "" {
+				article.EditedAt = published.EditedAt
+			}
+		}
+	}
+
+	createdAt := article.CreatedAt
+	if createdAt == "" {
+		createdAt = article.PublishedAt
+	}
+	updatedAt := article.EditedAt
+	if updatedAt == "" {
+		updatedAt = article.PublishedAt
+	}
+	if updatedAt == "" {
+		updatedAt = createdAt
+	}
+	return createdAt, updatedAt, nil
+}
+
+// articleChanged reports whether the article differs from the state, e.g.
+// because it has been edited on Forem.
+func articleChanged(d resourceGetter, article dev.Article) bool {
+	body, _ := getBody(d)
+	return d.Get("title").(string) != article.Title ||
+		d.Get("description").(string) != article.Description ||
+		normalizeMarkdown(stripFrontMatter(body)) != normalizeMarkdown(article.BodyMarkdown) ||
+		!equalStrings(expandStringList(d.Get("tags_all").([]interface{})), article.TagList) ||
+		d.Get("cover_image").(string) != article.CoverImage ||
+		d.Get("published").(bool) != article.Published
+}
+
+// getArticleUpdateFields returns the fields of the article whose arguments
+// have changed, so that an update leaves the fields edited on Forem alone.
+func getArticleUpdateFields(d *schema.ResourceData, abc dev.ArticleBodySchema) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if d.HasChange("title") {
+		fields["title"] = abc.Article.Title
+	}
+	// front_matter is empty in the state of articles created before it was
+	// introduced, which is not worth resending the body for.
+	if oldMode, _ := d.GetChange("front_matter"); d.HasChanges("body_markdown", "body_sha256") || (oldMode != "" && d.HasChange("front_matter")) {
+		fields["body_markdown"] = abc.Article.BodyMarkdown
+	}
+	if d.HasChange("published") {
+		fields["published"] = abc.Article.Published
+	}
+	if d.HasChange("series") || frontMatterSeriesChanged(d) {
+		fields["series"] = abc.Article.Series
+	}
+	if d.HasChange("cover_image") || frontMatterFieldChanged(d, func(fm *frontMatter) string { return fm.CoverImage }) {
+		fields["main_image"] = abc.Article.MainImage
+	}
+	if d.HasChange("canonical_url") || frontMatterFieldChanged(d, func(fm *frontMatter) string { return fm.CanonicalURL }) {
+		fields["canonical_url"] = abc.Article.CanonicalURL
+	}
+	if d.HasChange("description") || frontMatterFieldChanged(d, func(fm *frontMatter) string { return fm.Description }) {
+		fields["description"] = abc.Article.Description
+	}
+	if d.HasChanges("tags", "tags_all") {
+		tags := abc.Article.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		fields["tags"] = tags
+	}
+	if d.HasChanges("organization_id", "organization_username") {
+		fields["organization_id"] = abc.Article.OrganizationID
+	}
+	return fields
+}
+
+// updateArticle updates the given fields of the article only.
+// dev.Client.UpdateArticle always sends every field of the article.
+func (c *apiClient) updateArticle(ctx context.Context, id string, fields map[string]interface{}) error {
+	req, err := c.NewRequest(ctx, http.MethodPut, "/articles/"+id, map[string]interface{}{"article": fields})
+	if err != nil {
+		return err
+	}
+	return c.SendHttpRequest(req, nil)
+}
+
+// buildArticleBodySchema returns the body of the article, assigned to the organization
+// set by `organization_username` if any.
+func buildArticleBodySchema(ctx context.Context, client *apiClient, d *schema.ResourceData) (dev.ArticleBodySchema, error) {
+	if _, err := getBody(d); err != nil {
+		return dev.ArticleBodySchema{}, err
+	}
+	abc := getArticleBodySchemaFromResourceData(d, client.defaults)
+	orgID, err := resolveOrganizationUsername(ctx, client, d)
+	if err != nil {
+		return abc, err
+	}
+	if orgID != 0 {
+		abc.Article.OrganizationID = int32(orgID)
+	}
+	return abc, nil
+}
+
+func getArticleBodySchemaFromResourceData(d *schema.ResourceData, defaults providerDefaults) dev.ArticleBodySchema {
+	defaults = frontMatterDefaults(d, defaults)
+
 	var abc dev.ArticleBodySchema
 	abc.Article.Title = d.Get("title").(string)
-	abc.Article.BodyMarkdown = d.Get("body_markdown").(string)
+	body, _ := getBody(d)
+	abc.Article.BodyMarkdown = stripFrontMatter(body)
 
 	if v, ok := d.GetOk("published"); ok {
 		abc.Article.Published = v.(bool)
 	}
 	if v, ok := d.GetOk("series"); ok {
 		abc.Article.Series = v.(string)
+	} else {
+		abc.Article.Series = defaults.Series
 	}
+	fm := mergedFrontMatter(d)
 	if v, ok := d.GetOk("cover_image"); ok {
 		abc.Article.MainImage = v.(string)
+	} else {
+		abc.Article.MainImage = fm.CoverImage
 	}
 	if v, ok := d.GetOk("canonical_url"); ok {
 		abc.Article.CanonicalURL = v.(string)
+	} else if fm.CanonicalURL != "" {
+		abc.Article.CanonicalURL = fm.CanonicalURL
+	} else if defaults.CanonicalURLPrefix != "" {
+		abc.Article.CanonicalURL = defaultCanonicalURL(defaults.CanonicalURLPrefix, abc.Article.Title)
 	}
 	if v, ok := d.GetOk("description"); ok {
 		abc.Article.Description = v.(string)
+	} else {
+		abc.Article.Description = fm.Description
 	}
-	if v, ok := d.GetOk("tags"); ok {
-		tags := []string{}
-		for _, t := range v.([]interface{}) {
-			tags = append(tags, t.(string))
-		}
+	if tags := mergeTags(expandStringList(d.Get("tags").([]interface{})), defaults.Tags); len(tags) > 0 {
 		abc.Article.Tags = tags
 	}
 	if v, ok := d.GetOk("organization_id"); ok {
-		abc.Article.OrganizationID = v.(int32)
+		abc.Article.OrganizationID = int32(v.(int))
+	} else {
+		abc.Article.OrganizationID = int32(defaults.OrganizationID)
 	}
 	return abc
 }
@@ -0,0 +1,385 @@
+package forem
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	dev "github.com/karvounis/dev-client-go"
+)
+
+func TestResourceArticleRead_removesMissingArticleFromState(t *testing.T) {
+	var creates int
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			creates++
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `[{"id":1,"title":"another article"}]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}, 0)
+
+	d := schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":         "deleted article",
+		"body_markdown": "body",
+	})
+	d.SetId("42")
+
+	if diags := resourceArticleRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected article to be removed from state, got ID `%s`", d.Id())
+	}
+	if creates != 0 {
+		t.Errorf("expected no article to be created, got %d", creates)
+	}
+}
+
+func TestResourceArticleCreate_keepsMissingArticleInState(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			fmt.Fprint(w, `{"id":42,"title":"article"}`)
+		case r.URL.Query().Get("page") == "1":
+			fmt.Fprint(w, `[{"id":1,"title":"another article"}]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}, 0)
+	c.user = &dev.User{ID: 1, Username: "forem"}
+
+	d := schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+		"title":         "article",
+		"body_markdown": "body",
+	})
+
+	if diags := resourceArticleCreate(context.Background(), d, c); !diags.HasError() {
+		t.Fatal("expected an error for an article missing after it was created")
+	}
+	if d.Id() != "42" {
+		t.Errorf("expected the created article to be kept in state, got ID `%s`", d.Id())
+	}
+}
+
+func TestResourceArticleRead_removesArticleOnNotFound(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeDevError(w, http.StatusNotFound)
+	}, 0)
+
+	d := schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{})
+	d.SetId("42")
+
+	if diags := resourceArticleRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected article to be removed from state, got ID `%s`", d.Id())
+	}
+}
+
+func TestResourceArticleDelete_onDestroy(t *testing.T) {
+	cases := map[string]struct {
+		onDestroy   string
+		wantUpdate  bool
+		wantError   bool
+		wantWarning bool
+	}{
+		"unpublish": {onDestroy: onDestroyUnpublish, wantUpdate: true},
+		"abandon":   {onDestroy: onDestroyAbandon, wantWarning: true},
+		"error":     {onDestroy: onDestroyError, wantError: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var body string
+			var updates int
+			c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.Path != "/articles/42" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				updates++
+				b, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = string(b)
+				fmt.Fprint(w, `{"id":42}`)
+			}, 0)
+
+			d := schema.TestResourceDataRaw(t, resourceArticle().Schema, map[string]interface{}{
+				"title":         "article",
+				"body_file":     filepath.Join(t.TempDir(), "deleted.md"),
+				"published":     true,
+				"series":        "series",
+				"canonical_url": "https://example.com/article",
+				"tags":          []interface{}{"go"},
+				"on_destroy":    tc.onDestroy,
+			})
+			d.SetId("42")
+
+			diags := resourceArticleDelete(context.Background(), d, c)
+			if diags.HasError() != tc.wantError {
+				t.Fatalf("expected error: %t, got %v", tc.wantError, diags)
+			}
+			if gotWarning := len(diags) == 1 && diags[0].Severity == diag.Warning; gotWarning != tc.wantWarning {
+				t.Errorf("expected warning: %t, got %v", tc.wantWarning, diags)
+			}
+			if gotUpdate := updates == 1; gotUpdate != tc.wantUpdate {
+				t.Fatalf("expected update: %t, got %d updates", tc.wantUpdate, updates)
+			}
+			if want := `{"article":{"published":false}}`; tc.wantUpdate && body != want {
+				t.Errorf("expected body `%s`, got `%s`", want, body)
+			}
+		})
+	}
+}
+
+func TestGetArticleTimestamps(t *testing.T) {
+	var fetches int
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		switch r.URL.Path {
+		case "/articles/1":
+			fmt.Fprint(w, `{"id":1,"created_at":"2022-01-01T10:00:00Z","edited_at":"2022-01-03T10:00:00Z","published_at":"2022-01-02T10:00:00Z"}`)
+		case "/articles/2":
+			fmt.Fprint(w, `{"id":2,"created_at":"2022-01-01T10:00:00Z","published_at":"2022-01-02T10:00:00Z"}`)
+		default:
+			writeDevError(w, http.StatusNotFound)
+		}
+	}, 0)
+
+	cases := map[string]struct {
+		article       dev.Article
+		state         map[string]string
+		wantCreatedAt string
+		wantUpdatedAt string
+		wantFetches   int
+	}{
+		"edited": {
+			article:       dev.Article{ID: 1, Published: true, PublishedAt: "2022-01-02T10:00:00Z"},
+			wantCreatedAt: "2022-01-01T10:00:00Z",
+			wantUpdatedAt: "2022-01-03T10:00:00Z",
+			wantFetches:   1,
+		},
+		"never edited": {
+			article:       dev.Article{ID: 2, Published: true, PublishedAt: "2022-01-02T10:00:00Z"},
+			wantCreatedAt: "2022-01-01T10:00:00Z",
+			wantUpdatedAt: "2022-01-02T10:00:00Z",
+			wantFetches:   1,
+		},
+		"published article not found": {
+			article:       dev.Article{ID: 3, Published: true, PublishedAt: "2022-01-02T10:00:00Z"},
+			wantCreatedAt: "2022-01-02T10:00:00Z",
+			wantUpdatedAt: "2022-01-02T10:00:00Z",
+			wantFetches:   1,
+		},
+		"draft": {
+			article: dev.Article{ID: 4},
+		},
+		"cached": {
+			article:       dev.Article{ID: 1, Published: true, CreatedAt: "2022-01-01T10:00:00Z", EditedAt: "2022-01-04T10:00:00Z"},
+			wantCreatedAt: "2022-01-01T10:00:00Z",
+			wantUpdatedAt: "2022-01-04T10:00:00Z",
+		},
+		"in state": {
+			article:       dev.Article{ID: 1, Published: true, PublishedAt: "2022-01-02T10:00:00Z"},
+			state:         map[string]string{"published": "true", "created_at": "2022-01-01T10:00:00Z", "updated_at": "2022-01-02T10:00:00Z"},
+			wantCreatedAt: "2022-01-01T10:00:00Z",
+			wantUpdatedAt: "2022-01-02T10:00:00Z",
+		},
+		"edited on Forem": {
+			article:       dev.Article{ID: 1, Title: "edited", Published: true, PublishedAt: "2022-01-02T10:00:00Z"},
+			state:         map[string]string{"title": "article", "published": "true", "created_at": "2022-01-01T10:00:00Z", "updated_at": "2022-01-02T10:00:00Z"},
+			wantCreatedAt: "2022-01-01T10:00:00Z",
+			wantUpdatedAt: "2022-01-03T10:00:00Z",
+			wantFetches:   1,
+		},
+		"edited by the provider": {
+			article:       dev.Article{ID: 1, Published: true, PublishedAt: "2022-01-02T10:00:00Z"},
+			state:         map[string]string{"created_at": "2022-01-01T10:00:00Z"},
+			wantCreatedAt: "2022-01-01T10:00:00Z",
+			wantUpdatedAt: "2022-01-03T10:00:00Z",
+			wantFetches:   1,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fetches = 0
+			d := resourceArticle().Data(&terraform.InstanceState{ID: "1", Attributes: tc.state})
+			createdAt, updatedAt, err := getArticleTimestamps(context.Background(), c, tc.article, d)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if createdAt != tc.wantCreatedAt || updatedAt != tc.wantUpdatedAt {
+				t.Errorf("expected `%s` and `%s`, got `%s` and `%s`", tc.wantCreatedAt, tc.wantUpdatedAt, createdAt, updatedAt)
+			}
+			if fetches != tc.wantFetches {
+				t.Errorf("expected %d requests, got %d", tc.wantFetches, fetches)
+			}
+		})
+	}
+}
+
+func TestResourceArticleUpdate_sendsChangedFieldsOnly(t *testing.T) {
+	state := map[string]string{
+		"id":            "42",
+		"title":         "article",
+		"body_markdown": "body",
+		"body_sha256":   markdownSHA256("body"),
+		"published":     "false",
+		"cover_image":   "https://example.com/cover.png",
+		"tags.#":        "2",
+		"tags.0":        "go",
+		"tags.1":        "terraform",
+		"tags_all.#":    "2",
+		"tags_all.0":    "go",
+		"tags_all.1":    "terraform",
+		"on_destroy":    onDestroyUnpublish,
+	}
+	config := map[string]interface{}{
+		"title":         "article",
+		"body_markdown": "body",
+		"cover_image":   "https://example.com/cover.png",
+		"tags":          []interface{}{"go", "terraform"},
+		"on_destroy":    onDestroyUnpublish,
+	}
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		wantBody string
+	}{
+		"title": {
+			config:   map[string]interface{}{"title": "new title"},
+			wantBody: `{"article":{"title":"new title"}}`,
+		},
+		"tags and cover image": {
+			config:   map[string]interface{}{"tags": []interface{}{"go"}, "cover_image": "https://example.com/new.png"},
+			wantBody: `{"article":{"main_image":"https://example.com/new.png","tags":["go"]}}`,
+		},
+		"no tags": {
+			config:   map[string]interface{}{"tags": []interface{}{}},
+			wantBody: `{"article":{"tags":[]}}`,
+		},
+		"provider only argument": {
+			config: map[string]interface{}{"on_destroy": onDestroyAbandon},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var body string
+			c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut && r.URL.Path == "/articles/42":
+					b, err := ioutil.ReadAll(r.Body)
+					if err != nil {
+						t.Fatal(err)
+					}
+					body = string(b)
+					fmt.Fprint(w, `{"id":42}`)
+				case r.Method == http.MethodGet && r.URL.Query().Get("page") == "1":
+					fmt.Fprint(w, `[{"id":42,"title":"article"}]`)
+				case r.Method == http.MethodGet:
+					fmt.Fprint(w, `[]`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+			}, 0)
+
+			raw := map[string]interface{}{}
+			for k, v := range config {
+				raw[k] = v
+			}
+			for k, v := range tc.config {
+				raw[k] = v
+			}
+			d := testResourceDataDiff(t, resourceArticle(), state, raw, c)
+
+			if diags := resourceArticleUpdate(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if body != tc.wantBody {
+				t.Errorf("expected body `%s`, got `%s`", tc.wantBody, body)
+			}
+		})
+	}
+}
+
+// testResourceDataDiff returns the resource data of r planned from the state
+// to the config, so that d.HasChange reports the changed arguments.
+func testResourceDataDiff(t *testing.T, r *schema.Resource, state map[string]string, config map[string]interface{}, meta interface{}) *schema.ResourceData {
+	t.Helper()
+
+	s := &terraform.InstanceState{ID: state["id"], Attributes: state}
+	diff, err := r.Diff(context.Background(), s, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(s, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestResolveArticleImportID(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/articles/forem/published-article-1a2b":
+			fmt.Fprint(w, `{"id":1,"path":"/forem/published-article-1a2b"}`)
+		case "/articles/someone/their-article-3c4d":
+			fmt.Fprint(w, `{"id":3,"path":"/someone/their-article-3c4d"}`)
+		case "/articles/me/all":
+			if r.URL.Query().Get("page") != "1" {
+				fmt.Fprint(w, `[]`)
+				return
+			}
+			fmt.Fprint(w, `[{"id":1,"path":"/forem/published-article-1a2b","published":true},{"id":2,"path":"/forem/draft-article-temp-slug-42","published":false}]`)
+		default:
+			writeDevError(w, http.StatusNotFound)
+		}
+	}, 0)
+
+	cases := map[string]struct {
+		importID  string
+		want      string
+		wantError string
+	}{
+		"id":                  {importID: "42", want: "42"},
+		"url":                 {importID: "https://dev.to/forem/published-article-1a2b", want: "1"},
+		"url with slash":      {importID: "https://dev.to/forem/published-article-1a2b/", want: "1"},
+		"path":                {importID: "forem/published-article-1a2b", want: "1"},
+		"draft path":          {importID: "/forem/draft-article-temp-slug-42", want: "2"},
+		"draft url":           {importID: "https://dev.to/forem/draft-article-temp-slug-42", want: "2"},
+		"article of someone":  {importID: "someone/their-article-3c4d", wantError: "does not belong to the authenticated user"},
+		"unknown article":     {importID: "forem/unknown-article", wantError: "not found"},
+		"invalid import ID":   {importID: "published-article-1a2b", wantError: "expected an article ID"},
+		"too many path parts": {importID: "https://dev.to/forem/published-article-1a2b/comments", wantError: "expected an article ID"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolveArticleImportID(context.Background(), c, tc.importID)
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Fatalf("expected error containing `%s`, got %v", tc.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected ID `%s`, got `%s`", tc.want, got)
+			}
+		})
+	}
+}
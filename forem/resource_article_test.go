@@ -36,9 +36,10 @@ func TestAccArticle_createDraft(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resourceName, "canonical_url"),
 					resource.TestCheckResourceAttrSet(resourceName, "path"),
 					resource.TestCheckNoResourceAttr(resourceName, "series"),
-					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
+					resource.TestCheckNoResourceAttr(resourceName, "created_at"),
 					resource.TestCheckNoResourceAttr(resourceName, "updated_at"),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttrSet(resourceName, "last_modified_by_provider"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 					resource.TestCheckResourceAttr(resourceName, "published_at", ""),
 					resource.TestCheckResourceAttr(resourceName, "published_timestamp", ""),
 					resource.TestCheckResourceAttr(resourceName, "comments_count", strconv.Itoa(0)),
@@ -100,8 +101,9 @@ func TestAccArticle_publishAndEdit(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resourceName, "path"),
 					resource.TestCheckResourceAttr(resourceName, "series", series),
 					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
-					resource.TestCheckNoResourceAttr(resourceName, "updated_at"),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttrPair(resourceName, "updated_at", resourceName, "published_at"),
+					resource.TestCheckResourceAttrSet(resourceName, "last_modified_by_provider"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 					resource.TestCheckResourceAttrSet(resourceName, "published_at"),
 					resource.TestCheckResourceAttrSet(resourceName, "published_timestamp"),
 					resource.TestCheckResourceAttr(resourceName, "comments_count", strconv.Itoa(0)),
@@ -126,7 +128,8 @@ func TestAccArticle_publishAndEdit(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "series", series),
 					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
 					resource.TestCheckResourceAttrSet(resourceName, "updated_at"),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttrSet(resourceName, "last_modified_by_provider"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 					resource.TestCheckResourceAttrSet(resourceName, "published_at"),
 					resource.TestCheckResourceAttrSet(resourceName, "published_timestamp"),
 					resource.TestCheckResourceAttr(resourceName, "comments_count", strconv.Itoa(0)),
@@ -140,6 +143,37 @@ func TestAccArticle_publishAndEdit(t *testing.T) {
 	})
 }
 
+func TestAccArticle_publishAfter(t *testing.T) {
+	gofakeit.Seed(time.Now().UnixNano())
+
+	resourceName := "forem_article.test"
+	title := gofakeit.SentenceSimple()
+	bodyMarkdown := gofakeit.HipsterParagraph(2, 5, 10, "\n")
+	publishAfter := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "forem_article" "test" {
+	title         = %q
+	body_markdown = %q
+	publish_after = %q
+}`, title, bodyMarkdown, publishAfter),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "publish_after", publishAfter),
+					resource.TestCheckResourceAttr(resourceName, "publish_pending", "true"),
+					resource.TestCheckResourceAttr(resourceName, "published", "false"),
+					resource.TestCheckResourceAttr(resourceName, "published_at", ""),
+				),
+			},
+		},
+	})
+}
+
 func TestAccArticle_tooManyTags(t *testing.T) {
 	gofakeit.Seed(time.Now().UnixNano())
 
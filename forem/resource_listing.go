@@ -3,23 +3,25 @@ package forem
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	dev "github.com/karvounis/dev-client-go"
 )
 
 const (
-	maxListingTags              = 8
-	validListingExpiresAtFormat = `\d{2}\/\d{2}\/\d{4}`
+	maxListingTags      = 8
+	readListingsPerPage = 1000
 )
 
 var (
+	allowedListingOnDestroy  = []string{onDestroyUnpublish, onDestroyAbandon}
 	allowedListingActions    = []string{string(dev.ActionDraft), string(dev.ActionBump), string(dev.ActionPublish), string(dev.ActionUnpublish)}
 	allowedListingCategories = []string{
 		string(dev.ListingCategoryCfp),
@@ -37,8 +39,10 @@ var (
 )
 
 func resourceListing() *schema.Resource {
-	return &schema.Resource{
-		Description: "`forem_listing` resource creates and updates a particular listing. A listing is a classified ad that users create on Forem. They can be related to conference announcements, job offers, mentorships, upcoming events and more." +
+	r := &schema.Resource{
+		Description: "`forem_listing` resource creates and updates a particular listing. A listing is a classified ad that users create on Forem. They can be related to conference announcements, job offers, mentorships, upcoming events and more. " +
+			"The API does not allow deleting listings, so by default they are unpublished on destroy. " +
+			"Listings can be imported by their ID or, once published, by their slug." +
 			"\n\n## API Docs\n\n" +
 			"- https://developers.forem.com/api#operation/createListing\n" +
 			"- https://developers.forem.com/api#operation/updateListing",
@@ -46,8 +50,16 @@ func resourceListing() *schema.Resource {
 		CreateContext: resourceListingCreate,
 		UpdateContext: resourceListingUpdate,
 		DeleteContext: resourceListingDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffTagsAll(maxListingTags, nil),
+			customizeDiffBodySHA256,
+			customizeDiffOrganization,
+			customizeDiffListingExpiresAt,
+			customizeDiffBumpTriggers,
+		),
+		SchemaVersion: 1,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceListingImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -61,9 +73,23 @@ func resourceListing() *schema.Resource {
 				Required:    true,
 			},
 			"body_markdown": {
-				Description: "The body of the listing in Markdown format.",
+				Description:      "The body of the listing in Markdown format. Line endings and trailing whitespace are normalized the way Forem stores the body, keeping the two trailing spaces of hard line breaks.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"body_markdown", "body_file"},
+				StateFunc:        normalizeMarkdownStateFunc,
+				DiffSuppressFunc: suppressEquivalentMarkdown,
+			},
+			"body_file": {
+				Description:  "Path to a file with the body of the listing in Markdown format, read at plan and apply time. Only its checksum is tracked in `body_sha256`, which keeps long bodies out of the plan and the state. Alternative to `body_markdown`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"body_markdown", "body_file"},
+			},
+			"body_sha256": {
+				Description: "SHA-256 checksum of the normalized `body_markdown` or content of `body_file`, which is easier to follow in the plan than the body of a long listing.",
 				Type:        schema.TypeString,
-				Required:    true,
+				Computed:    true,
 			},
 			"category": {
 				Description:  "The category that the listing belongs to.",
@@ -72,10 +98,16 @@ func resourceListing() *schema.Resource {
 				ValidateFunc: validation.StringInSlice(allowedListingCategories, false),
 			},
 			"tags": {
-				Description: "List of tags related to the listing.",
+				Description: "List of tags related to the listing. Tags may only contain letters and digits and are stored in lowercase, the way Forem does.",
 				Type:        schema.TypeList,
 				Optional:    true,
 				MaxItems:    maxListingTags,
+				Elem:        tagElemSchema(),
+			},
+			"tags_all": {
+				Description: "List of tags of the listing, including the default tags of the provider.",
+				Type:        schema.TypeList,
+				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			"contact_via_connect": {
@@ -85,10 +117,17 @@ func resourceListing() *schema.Resource {
 				Default:     false,
 			},
 			"expires_at": {
-				Description:  "Date and time of expiration.",
+				Description: "Date of expiration, in the format `MM/DD/YYYY` or as an RFC3339 timestamp, which is stored as `MM/DD/YYYY`. " +
+					fmt.Sprintf("It must be within the next %d days, the maximum lifetime of a listing, which is checked at plan time when it changes.", maxListingLifetimeDays),
 				Type:         schema.TypeString,
 				Optional:     true,
-				ValidateFunc: validation.StringMatch(regexp.MustCompile(validListingExpiresAtFormat), fmt.Sprintf("expected to be of the format `%s`", validListingExpiresAtFormat)),
+				ValidateFunc: validateListingExpiresAt,
+				StateFunc:    normalizeListingExpiresAtStateFunc,
+			},
+			"expired": {
+				Description: "Whether the listing has expired according to `expires_at`.",
+				Type:        schema.TypeBool,
+				Computed:    true,
 			},
 			"location": {
 				Description: "Geographical area or city for the listing.",
@@ -96,15 +135,31 @@ func resourceListing() *schema.Resource {
 				Optional:    true,
 			},
 			"action": {
-				Description:  "Set it to `draft` to create an unpublished listing.",
+				Description:  "Set it to `draft` to create an unpublished listing. Once set, it is reconciled with whether the listing is published on Forem. Use `bump_triggers` to bump the listing more than once.",
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.StringInSlice(allowedListingActions, false),
 			},
 			"organization_id": {
-				Description: "The id of the organization the user is creating the listing for. Only users belonging to an organization can assign the listing to it.",
-				Type:        schema.TypeInt,
-				Optional:    true,
+				Description:   "The id of the organization the user is creating the listing for. Only users belonging to an organization can assign the listing to it. The membership of the authenticated user is validated at plan time.",
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"organization_username"},
+			},
+			"organization_username": {
+				Description:   "Username of the organization the user is creating the listing for, resolved to its ID by the provider. Alternative to `organization_id`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"organization_id"},
+			},
+			"on_destroy": {
+				Description: "What to do with the listing on destroy. " +
+					"`unpublish` removes the listing from the listings board, " +
+					"`abandon` leaves the listing as it is and only removes it from the state.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      onDestroyUnpublish,
+				ValidateFunc: validation.StringInSlice(allowedListingOnDestroy, false),
 			},
 			"slug": {
 				Description: "Slug of the listing.",
@@ -116,41 +171,172 @@ func resourceListing() *schema.Resource {
 				Type:        schema.TypeBool,
 				Computed:    true,
 			},
-			"user": {
-				Description: "User object of the listing.",
-				Type:        schema.TypeMap,
+			"user":         userSchema("User object of the listing."),
+			"organization": organizationSchema("Organization object of the listing."),
+			"created_at": {
+				Description: "When the listing was created.",
+				Type:        schema.TypeString,
 				Computed:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
-			"organization": {
-				Description: "Organization object of the listing.",
-				Type:        schema.TypeMap,
+			"updated_at": {
+				Description: "When the listing was last updated by the provider.",
+				Type:        schema.TypeString,
 				Computed:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Deprecated:  "The API does not return when a listing was last edited, so this is only set on updates by the provider. Use `last_modified_by_provider` instead.",
 			},
-			"created_at": {
-				Description: "When the listing was created.",
+			"last_modified_by_provider": {
+				Description: "When the listing was last created or updated by the provider, according to the local clock.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
-			"updated_at": {
-				Description: "When the listing was updated.",
+			"bump_triggers": {
+				Description: "Arbitrary map of values that bumps the listing whenever any of them changes, similar to the `triggers` of a `null_resource`. " +
+					"Setting them on creation or removing them does not bump the listing.",
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"last_bumped_at": {
+				Description: "When the listing was last bumped by the provider through `bump_triggers`, according to the local clock.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
 		},
 	}
+	r.StateUpgraders = []schema.StateUpgrader{
+		stringMapsStateUpgraderV0(r, "user", "organization"),
+	}
+	return r
+}
+
+// resourceListingImport imports a listing by its ID or its slug, as long as it
+// belongs to the authenticated user or to one of their organizations, since
+// every update of the listing would fail otherwise.
+func resourceListingImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*apiClient)
+
+	listing, err := getListingForImport(ctx, client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkListingOwnership(ctx, client, listing); err != nil {
+		return nil, err
+	}
+	d.SetId(strconv.Itoa(int(listing.ID)))
+	d.Set("on_destroy", onDestroyUnpublish)
+	return []*schema.ResourceData{d}, nil
+}
+
+// getListingForImport returns the listing with the given ID or slug. The API
+// has no endpoint that returns a listing by its slug, so it is looked up among
+// the published listings.
+func getListingForImport(ctx context.Context, client *apiClient, importID string) (*dev.Listing, error) {
+	importID = strings.TrimSpace(importID)
+	if _, err := strconv.Atoi(importID); err == nil {
+		tflog.Debug(ctx, fmt.Sprintf("Getting listing with ID: %s", importID))
+		listing, err := client.GetListingByID(importID)
+		if isNotFound(err) {
+			return nil, fmt.Errorf("listing `%s` not found", importID)
+		}
+		return listing, err
+	}
+
+	for page := int32(1); ; page++ {
+		tflog.Debug(ctx, fmt.Sprintf("Getting published listings with page: %d and perPage: %d", page, readListingsPerPage))
+		listings, err := client.GetPublishedListings(dev.ListingQueryParams{Page: page, PerPage: readListingsPerPage})
+		if err != nil {
+			return nil, err
+		}
+		for i := range listings {
+			if listings[i].Slug == importID {
+				return &listings[i], nil
+			}
+		}
+		if len(listings) < readListingsPerPage {
+			break
+		}
+	}
+	return nil, fmt.Errorf("published listing with slug `%s` not found, drafts can only be imported by their ID", importID)
+}
+
+// checkListingOwnership makes sure that the listing belongs to the
+// authenticated user or to one of their organizations.
+func checkListingOwnership(ctx context.Context, client *apiClient, listing *dev.Listing) error {
+	user, err := client.authenticatedUser(ctx)
+	if err != nil {
+		return err
+	}
+	if listing.User != nil && listing.User.Username == user.Username {
+		return nil
+	}
+	if listing.Organization != nil {
+		org, err := client.getOrganization(ctx, listing.Organization.Username)
+		if err != nil {
+			return err
+		}
+		member, err := client.isOrganizationMember(ctx, org)
+		if err != nil {
+			return err
+		}
+		if member {
+			return nil
+		}
+	}
+
+	owner := "an unknown user"
+	if listing.User != nil {
+		owner = fmt.Sprintf("`%s`", listing.User.Username)
+	}
+	return fmt.Errorf("listing `%d` belongs to %s, not to the authenticated user `%s` or one of their organizations, so it cannot be managed by the provider", listing.ID, owner, user.Username)
 }
 
-// TODO: Waiting for API to allow deletion of a listing
 func resourceListingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient)
+
+	if d.Get("on_destroy").(string) == onDestroyAbandon {
+		tflog.Debug(ctx, fmt.Sprintf("Abandoning listing with ID: %s", d.Id()))
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Listing abandoned",
+			Detail:   fmt.Sprintf("Listing with ID `%s` has been removed from the state but still exists on Forem, since `on_destroy` is set to `%s`.", d.Id(), onDestroyAbandon),
+		}}
+	}
+
+	if _, err := getBody(d); err != nil {
+		// The body file may be gone by the time the listing is destroyed, in
+		// which case the listing is unpublished with its body on Forem.
+		resp, err := client.GetListingByID(d.Id())
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("body_file", "")
+		d.Set("body_markdown", resp.BodyMarkdown)
+	}
+
+	lbc, err := buildListingBodySchema(ctx, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	lbc.Listing.Action = dev.ActionUnpublish
+	tflog.Debug(ctx, fmt.Sprintf("Unpublishing listing with ID: %s", d.Id()))
+	if _, err := client.UpdateListing(d.Id(), lbc, nil); err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Unpublished listing with ID: %s", d.Id()))
+
 	return nil
 }
 
 func resourceListingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
-	lbc := getListingBodySchemaFromResourceData(d)
+	lbc, err := buildListingBodySchema(ctx, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	tflog.Debug(ctx, fmt.Sprintf("Creating listing with title: `%s` and category: `%s`", lbc.Listing.Title, lbc.Listing.Category))
 	resp, err := client.CreateListing(lbc, nil)
 	if err != nil {
@@ -159,32 +345,50 @@ func resourceListingCreate(ctx context.Context, d *schema.ResourceData, meta int
 	tflog.Debug(ctx, fmt.Sprintf("Created listing with ID: %d", resp.ID))
 
 	d.SetId(strconv.Itoa(int(resp.ID)))
-	d.Set("created_at", time.Now().Format(time.RFC3339))
+	d.Set("last_modified_by_provider", time.Now().Format(time.RFC3339))
 
 	return resourceListingRead(ctx, d, meta)
 }
 
 func resourceListingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
 	tflog.Debug(ctx, fmt.Sprintf("Updating listing with ID: %s", d.Id()))
-	lbc := getListingBodySchemaFromResourceData(d)
+	lbc, err := buildListingBodySchema(ctx, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	bump := bumpTriggered(d)
+	if bump {
+		tflog.Debug(ctx, fmt.Sprintf("Bumping listing with ID: %s", d.Id()))
+		lbc.Listing.Action = dev.ActionBump
+	}
 	if _, err := client.UpdateListing(d.Id(), lbc, nil); err != nil {
 		return diag.FromErr(err)
 	}
 	tflog.Debug(ctx, fmt.Sprintf("Updated listing with ID: %s", d.Id()))
 
-	d.Set("updated_at", time.Now().Format(time.RFC3339))
+	now := time.Now().Format(time.RFC3339)
+	d.Set("updated_at", now)
+	d.Set("last_modified_by_provider", now)
+	if bump {
+		d.Set("last_bumped_at", now)
+	}
 
 	return resourceListingRead(ctx, d, meta)
 }
 
 func resourceListingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*dev.Client)
+	client := meta.(*apiClient)
 
-	id := d.Get("id").(string)
+	id := d.Id()
 	tflog.Debug(ctx, fmt.Sprintf("Getting listing with ID: %s", id))
-	resp, err := client.GetListingByID(id)
+	resp, err := client.getListing(ctx, id)
+	if isNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("Listing with ID: %s not found, removing it from state", id))
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -192,47 +396,90 @@ func resourceListingRead(ctx context.Context, d *schema.ResourceData, meta inter
 
 	d.SetId(id)
 	d.Set("title", resp.Title)
-	d.Set("body_markdown", resp.BodyMarkdown)
+	// The body of `body_file` is kept out of the state, only its checksum is
+	// tracked. A missing `body_file` is reported by the next plan.
+	body := resp.BodyMarkdown
+	if d.Get("body_file").(string) == "" {
+		d.Set("body_markdown", body)
+	} else if b, err := getBody(d); err == nil && normalizeMarkdown(b) == normalizeMarkdown(body) {
+		body = b
+	}
+	d.Set("body_sha256", markdownSHA256(body))
 	d.Set("slug", resp.Slug)
 	d.Set("category", resp.Category)
 	d.Set("published", resp.Published)
-	d.Set("tags", resp.Tags)
-
-	if resp.User != nil {
-		d.Set("user", map[string]interface{}{
-			"name":             resp.User.Name,
-			"username":         resp.User.Username,
-			"twitter_username": resp.User.TwitterUsername,
-			"github_username":  resp.User.GithubUsername,
-			"website_url":      resp.User.WebsiteURL,
-			"profile_image":    resp.User.ProfileImage,
-		})
-	} else {
-		d.Set("user", map[string]interface{}{})
+	d.Set("created_at", resp.CreatedAt)
+	d.Set("tags", withoutDefaultTags(resp.Tags, expandStringList(d.Get("tags").([]interface{})), client.defaults.Tags))
+	d.Set("tags_all", resp.Tags)
+	d.Set("action", flattenListingAction(d.Get("action").(string), resp.Published))
+
+	// A null `expires_at` or `location` means that it has been removed.
+	if resp.ExpiresAt.Present {
+		expiresAt, err := flattenListingExpiresAt(resp.ExpiresAt.Value)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("expires_at", expiresAt)
+	}
+	d.Set("expired", listingExpired(d.Get("expires_at").(string), time.Now()))
+	if resp.Location.Present {
+		d.Set("location", resp.Location.Value)
+	}
+	if resp.ContactViaConnect != nil {
+		d.Set("contact_via_connect", *resp.ContactViaConnect)
+	}
+	if err := setListingOrganization(ctx, client, d, resp); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if resp.Organization != nil {
-		d.Set("organization", map[string]interface{}{
-			"name":             resp.Organization.Name,
-			"username":         resp.Organization.Username,
-			"slug":             resp.Organization.Slug,
-			"profile_image":    resp.Organization.ProfileImage,
-			"profile_image_90": resp.Organization.ProfileImage90,
-		})
-	} else {
-		d.Set("organization", map[string]interface{}{})
+	user, err := flattenUser(ctx, client, resp.User)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("user", user)
+
+	organization, err := flattenOrganization(ctx, client, resp.Organization)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	d.Set("organization", organization)
 
 	return nil
 }
 
-func getListingBodySchemaFromResourceData(d *schema.ResourceData) dev.ListingBodySchema {
+// buildListingBodySchema returns the body of the listing, assigned to the organization
+// set by `organization_username` if any.
+func buildListingBodySchema(ctx context.Context, client *apiClient, d *schema.ResourceData) (dev.ListingBodySchema, error) {
+	body, err := getBody(d)
+	if err != nil {
+		return dev.ListingBodySchema{}, err
+	}
+	lbc, err := getListingBodySchemaFromResourceData(d, client.defaults)
+	if err != nil {
+		return lbc, err
+	}
+	lbc.Listing.BodyMarkdown = body
+	orgID, err := resolveOrganizationUsername(ctx, client, d)
+	if err != nil {
+		return lbc, err
+	}
+	if orgID != 0 {
+		lbc.Listing.OrganizationID = int64(orgID)
+	}
+	return lbc, nil
+}
+
+func getListingBodySchemaFromResourceData(d *schema.ResourceData, defaults providerDefaults) (dev.ListingBodySchema, error) {
 	var lbc dev.ListingBodySchema
 	lbc.Listing.Title = d.Get("title").(string)
 	lbc.Listing.BodyMarkdown = d.Get("body_markdown").(string)
 	lbc.Listing.Category = dev.ListingCategory(d.Get("category").(string))
 	if v, ok := d.GetOk("expires_at"); ok {
-		lbc.Listing.ExpiresAt = v.(string)
+		expiresAt, err := parseListingExpiresAt(v.(string))
+		if err != nil {
+			return lbc, err
+		}
+		lbc.Listing.ExpiresAt = expiresAt.Format(listingExpiresAtLayout)
 	}
 	if v, ok := d.GetOk("contact_via_connect"); ok {
 		lbc.Listing.ContactViaConnect = v.(bool)
@@ -243,12 +490,13 @@ func getListingBodySchemaFromResourceData(d *schema.ResourceData) dev.ListingBod
 	if v, ok := d.GetOk("action"); ok {
 		lbc.Listing.Action = dev.Action(v.(string))
 	}
-	if v, ok := d.GetOk("tags"); ok {
-		tags := []string{}
-		for _, t := range v.([]interface{}) {
-			tags = append(tags, t.(string))
-		}
+	if tags := mergeTags(expandStringList(d.Get("tags").([]interface{})), defaults.Tags); len(tags) > 0 {
 		lbc.Listing.Tags = tags
 	}
-	return lbc
+	if v, ok := d.GetOk("organization_id"); ok {
+		lbc.Listing.OrganizationID = int64(v.(int))
+	} else {
+		lbc.Listing.OrganizationID = int64(defaults.OrganizationID)
+	}
+	return lbc, nil
 }
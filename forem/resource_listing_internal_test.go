@@ -0,0 +1,334 @@
+package forem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	dev "github.com/karvounis/dev-client-go"
+)
+
+func TestResourceListingRead_removesListingOnNotFound(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeDevError(w, http.StatusNotFound)
+	}, 0)
+
+	d := schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{})
+	d.SetId("42")
+
+	if diags := resourceListingRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected listing to be removed from state, got ID `%s`", d.Id())
+	}
+}
+
+func TestResourceListingDelete_unpublishesByDefault(t *testing.T) {
+	var body dev.ListingBodySchema
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/listings/42" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprint(w, `{"id":42}`)
+	}, 0)
+
+	d := schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+		"title":         "listing",
+		"body_markdown": "body",
+		"category":      string(dev.ListingCategoryJobs),
+	})
+	d.SetId("42")
+
+	if diags := resourceListingDelete(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if body.Listing.Action != dev.ActionUnpublish {
+		t.Errorf("expected action `%s`, got `%s`", dev.ActionUnpublish, body.Listing.Action)
+	}
+}
+
+func TestResourceListingDelete_abandon(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}, 0)
+
+	d := schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+		"on_destroy": onDestroyAbandon,
+	})
+	d.SetId("42")
+
+	diags := resourceListingDelete(context.Background(), d, c)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("expected a single warning, got %v", diags)
+	}
+}
+
+func TestResourceListingImport(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/me":
+			fmt.Fprint(w, `{"id":1,"username":"forem"}`)
+		case "/listings/1":
+			fmt.Fprint(w, `{"id":1,"slug":"my-listing-1a","user":{"username":"forem"}}`)
+		case "/listings/2":
+			fmt.Fprint(w, `{"id":2,"slug":"org-listing-2b","user":{"username":"someone"},"organization":{"username":"acme"}}`)
+		case "/listings/3":
+			fmt.Fprint(w, `{"id":3,"slug":"their-listing-3c","user":{"username":"someone"}}`)
+		case "/listings/4":
+			fmt.Fprint(w, `{"id":4,"slug":"other-org-listing-4d","user":{"username":"someone"},"organization":{"username":"other"}}`)
+		case "/listings":
+			if r.URL.Query().Get("page") != "1" {
+				fmt.Fprint(w, `[]`)
+				return
+			}
+			fmt.Fprint(w, `[{"id":1,"slug":"my-listing-1a","user":{"username":"forem"}},{"id":3,"slug":"their-listing-3c","user":{"username":"someone"}}]`)
+		case "/organizations/acme":
+			fmt.Fprint(w, `{"id":42,"username":"acme"}`)
+		case "/organizations/other":
+			fmt.Fprint(w, `{"id":43,"username":"other"}`)
+		case "/organizations/acme/users":
+			fmt.Fprint(w, `[{"id":2,"username":"someone"},{"id":1,"username":"forem"}]`)
+		case "/organizations/other/users":
+			fmt.Fprint(w, `[{"id":2,"username":"someone"}]`)
+		default:
+			writeDevError(w, http.StatusNotFound)
+		}
+	}, 0)
+
+	cases := map[string]struct {
+		importID  string
+		want      string
+		wantError string
+	}{
+		"id":                    {importID: "1", want: "1"},
+		"slug":                  {importID: "my-listing-1a", want: "1"},
+		"listing of a member":   {importID: "2", want: "2"},
+		"listing of someone":    {importID: "their-listing-3c", wantError: "belongs to `someone`, not to the authenticated user `forem`"},
+		"listing of other org":  {importID: "4", wantError: "belongs to `someone`"},
+		"unknown id":            {importID: "5", wantError: "listing `5` not found"},
+		"unknown or draft slug": {importID: "draft-listing-5e", wantError: "drafts can only be imported by their ID"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := resourceListing().TestResourceData()
+			d.SetId(tc.importID)
+
+			got, err := resourceListingImport(context.Background(), d, c)
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Fatalf("expected error containing `%s`, got %v", tc.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(got) != 1 || got[0].Id() != tc.want {
+				t.Fatalf("expected listing `%s` to be imported, got %v", tc.want, got)
+			}
+			if got[0].Get("on_destroy") != onDestroyUnpublish {
+				t.Errorf("expected default `on_destroy`, got `%s`", got[0].Get("on_destroy"))
+			}
+		})
+	}
+}
+
+func TestResourceListingRead_detectsDrift(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/listings/42":
+			fmt.Fprint(w, `{"id":42,"title":"listing","body_markdown":"body","category":"jobs","published":false,`+
+				`"expires_at":"2022-04-01T00:00:00.000Z","location":"Berlin","contact_via_connect":true,"organization_id":43,`+
+				`"user":{"id":1,"username":"forem"},"organization":{"username":"other"}}`)
+		case "/organizations/other":
+			fmt.Fprint(w, `{"id":43,"username":"other"}`)
+		default:
+			writeDevError(w, http.StatusNotFound)
+		}
+	}, 0)
+
+	d := schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+		"title":               "listing",
+		"body_markdown":       "body",
+		"category":            string(dev.ListingCategoryJobs),
+		"expires_at":          "03/01/2022",
+		"location":            "Athens",
+		"contact_via_connect": false,
+		"action":              string(dev.ActionPublish),
+		"organization_id":     42,
+	})
+	d.SetId("42")
+
+	if diags := resourceListingRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	want := map[string]interface{}{
+		"expires_at":          "04/01/2022",
+		"location":            "Berlin",
+		"contact_via_connect": true,
+		"action":              string(dev.ActionUnpublish),
+		"organization_id":     43,
+	}
+	for k, v := range want {
+		if got := d.Get(k); got != v {
+			t.Errorf("expected `%s` to be `%v`, got `%v`", k, v, got)
+		}
+	}
+}
+
+func TestResourceListingRead_detectsRemovedValues(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/listings/42":
+			fmt.Fprint(w, `{"id":42,"title":"listing","body_markdown":"body","category":"jobs","published":true,`+
+				`"expires_at":null,"location":null,"user":{"id":1,"username":"forem"}}`)
+		default:
+			writeDevError(w, http.StatusNotFound)
+		}
+	}, 0)
+
+	d := schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+		"title":         "listing",
+		"body_markdown": "body",
+		"category":      string(dev.ListingCategoryJobs),
+		"expires_at":    "03/01/2022",
+		"location":      "Athens",
+	})
+	d.SetId("42")
+
+	if diags := resourceListingRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	for _, k := range []string{"expires_at", "location"} {
+		if got := d.Get(k); got != "" {
+			t.Errorf("expected `%s` to be empty, got `%v`", k, got)
+		}
+	}
+	if d.Get("expired").(bool) {
+		t.Error("expected a listing without `expires_at` not to be expired")
+	}
+}
+
+func TestResourceListingRead_keepsDefaultOrganization(t *testing.T) {
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/listings/42":
+			fmt.Fprint(w, `{"id":42,"title":"listing","body_markdown":"body","category":"jobs","published":true,`+
+				`"user":{"id":1,"username":"forem"},"organization":{"username":"acme"}}`)
+		case "/organizations/acme":
+			fmt.Fprint(w, `{"id":42,"username":"acme"}`)
+		default:
+			writeDevError(w, http.StatusNotFound)
+		}
+	}, 0)
+	c.defaults.OrganizationID = 42
+
+	d := schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+		"title":         "listing",
+		"body_markdown": "body",
+		"category":      string(dev.ListingCategoryJobs),
+		"location":      "Athens",
+	})
+	d.SetId("42")
+
+	if diags := resourceListingRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got := d.Get("organization_id"); got != 0 {
+		t.Errorf("expected the default organization to be left out of `organization_id`, got `%v`", got)
+	}
+	if got := d.Get("location"); got != "Athens" {
+		t.Errorf("expected `location` to be kept when the API leaves it out, got `%v`", got)
+	}
+}
+
+func TestResourceListingUpdate_bumpTriggers(t *testing.T) {
+	state := map[string]string{
+		"id":                 "42",
+		"title":              "listing",
+		"body_markdown":      "body",
+		"body_sha256":        markdownSHA256("body"),
+		"category":           string(dev.ListingCategoryJobs),
+		"on_destroy":         onDestroyUnpublish,
+		"bump_triggers.%":    "1",
+		"bump_triggers.week": "1",
+	}
+
+	cases := map[string]struct {
+		triggers interface{}
+		wantBump bool
+	}{
+		"changed":   {triggers: map[string]interface{}{"week": "2"}, wantBump: true},
+		"added":     {triggers: map[string]interface{}{"week": "1", "event": "launch"}, wantBump: true},
+		"unchanged": {triggers: map[string]interface{}{"week": "1"}},
+		"removed":   {},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var body dev.ListingBodySchema
+			c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut && r.URL.Path == "/listings/42":
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Fatal(err)
+					}
+					fmt.Fprint(w, `{"id":42}`)
+				case r.Method == http.MethodGet && r.URL.Path == "/listings/42":
+					fmt.Fprint(w, `{"id":42,"title":"listing","body_markdown":"body","category":"jobs","published":true}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+			}, 0)
+
+			config := map[string]interface{}{
+				"title":         "listing",
+				"body_markdown": "body",
+				"category":      string(dev.ListingCategoryJobs),
+				"location":      "Athens",
+			}
+			if tc.triggers != nil {
+				config["bump_triggers"] = tc.triggers
+			}
+			d := testResourceDataDiff(t, resourceListing(), state, config, c)
+
+			if diags := resourceListingUpdate(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if gotBump := body.Listing.Action == dev.ActionBump; gotBump != tc.wantBump {
+				t.Errorf("expected bump: %t, got action `%s`", tc.wantBump, body.Listing.Action)
+			}
+			if gotBumpedAt := d.Get("last_bumped_at").(string) != ""; gotBumpedAt != tc.wantBump {
+				t.Errorf("expected `last_bumped_at` to be set: %t, got `%s`", tc.wantBump, d.Get("last_bumped_at"))
+			}
+		})
+	}
+}
+
+func TestGetListingBodySchemaFromResourceData_expiresAt(t *testing.T) {
+	for _, expiresAt := range []string{"03/01/2022", "2022-03-01T18:30:00Z", "2022-03-01T23:30:00-05:00"} {
+		t.Run(expiresAt, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceListing().Schema, map[string]interface{}{
+				"title":         "listing",
+				"body_markdown": "body",
+				"category":      string(dev.ListingCategoryJobs),
+				"expires_at":    expiresAt,
+			})
+			lbc, err := getListingBodySchemaFromResourceData(d, providerDefaults{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if lbc.Listing.ExpiresAt != "03/01/2022" {
+				t.Errorf("expected `expires_at` `03/01/2022`, got `%s`", lbc.Listing.ExpiresAt)
+			}
+		})
+	}
+}
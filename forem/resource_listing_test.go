@@ -33,7 +33,7 @@ func TestAccListing_draft(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "tags.#", strconv.Itoa(len(lbc.Listing.Tags))),
 					resource.TestCheckNoResourceAttr(resourceName, "expires_at"),
 					resource.TestCheckNoResourceAttr(resourceName, "location"),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 				),
 			},
 		},
@@ -60,7 +60,8 @@ func TestAccListing_publish(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "tags.#", strconv.Itoa(len(lbc.Listing.Tags))),
 					resource.TestCheckResourceAttr(resourceName, "location", lbc.Listing.Location),
 					resource.TestCheckResourceAttr(resourceName, "expires_at", lbc.Listing.ExpiresAt),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttr(resourceName, "expired", "false"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 					resource.TestCheckNoResourceAttr(resourceName, "action"),
 				),
 			},
@@ -93,10 +94,11 @@ func TestAccListing_publishAndEdit(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "tags.#", strconv.Itoa(len(lbc.Listing.Tags))),
 					resource.TestCheckResourceAttr(resourceName, "location", lbc.Listing.Location),
 					resource.TestCheckResourceAttr(resourceName, "expires_at", lbc.Listing.ExpiresAt),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 					resource.TestCheckNoResourceAttr(resourceName, "action"),
 					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
 					resource.TestCheckNoResourceAttr(resourceName, "updated_at"),
+					resource.TestCheckResourceAttrSet(resourceName, "last_modified_by_provider"),
 				),
 			},
 			{
@@ -110,9 +112,10 @@ func TestAccListing_publishAndEdit(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "tags.#", strconv.Itoa(len(lbcEdit.Listing.Tags))),
 					resource.TestCheckResourceAttr(resourceName, "location", lbcEdit.Listing.Location),
 					resource.TestCheckResourceAttr(resourceName, "expires_at", lbcEdit.Listing.ExpiresAt),
-					resource.TestCheckResourceAttrSet(resourceName, "user.username"),
+					resource.TestCheckResourceAttrSet(resourceName, "user.0.username"),
 					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
 					resource.TestCheckResourceAttrSet(resourceName, "updated_at"),
+					resource.TestCheckResourceAttrSet(resourceName, "last_modified_by_provider"),
 				),
 			},
 		},
@@ -177,7 +180,7 @@ func getListingBodySchemaToPublish(action dev.Action) dev.ListingBodySchema {
 			BodyMarkdown:      gofakeit.Paragraph(1, 2, 5, "\n"),
 			Category:          dev.ListingCategory(gofakeit.RandomString([]string{string(dev.ListingCategoryCfp), string(dev.ListingCategoryEvents), string(dev.ListingCategoryMisc)})),
 			Tags:              []string{strings.ToLower(gofakeit.Word()), strings.ToLower(gofakeit.Word()), strings.ToLower(gofakeit.Word())},
-			ExpiresAt:         gofakeit.DateRange(time.Now(), time.Now().AddDate(0, 0, gofakeit.IntRange(1, 10))).Format("02/01/2004"),
+			ExpiresAt:         gofakeit.DateRange(time.Now(), time.Now().AddDate(0, 0, gofakeit.IntRange(1, 10))).Format("01/02/2006"),
 			ContactViaConnect: gofakeit.Bool(),
 			Location:          gofakeit.City(),
 			Action:            action,
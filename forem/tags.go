@@ -0,0 +1,76 @@
+package forem
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// maxTagLength is the maximum number of characters of a Forem tag.
+const maxTagLength = 30
+
+var tagRegexp = regexp.MustCompile(`^[\p{L}\p{N}]+$`)
+
+// tagElemSchema returns the schema of a tag, which Forem only accepts when it
+// is made of letters and digits and stores in lowercase.
+func tagElemSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		ValidateFunc: validateTag,
+		StateFunc:    normalizeTagStateFunc,
+	}
+}
+
+func validateTag(v interface{}, k string) ([]string, []error) {
+	tag, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if err := checkTag(tag); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// checkTag reports why Forem would reject or rewrite the tag, if it would.
+func checkTag(tag string) error {
+	if !tagRegexp.MatchString(tag) {
+		return fmt.Errorf("tag `%s` must only contain letters and digits", tag)
+	}
+	if utf8.RuneCountInString(tag) > maxTagLength {
+		return fmt.Errorf("tag `%s` is longer than %d characters", tag, maxTagLength)
+	}
+	return nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(tag)
+}
+
+func normalizeTagStateFunc(v interface{}) string {
+	return normalizeTag(v.(string))
+}
+
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, t := range tags {
+		normalized = append(normalized, normalizeTag(t))
+	}
+	return normalized
+}
+
+// duplicateTag returns the first tag that is set more than once, once
+// normalized, if any.
+func duplicateTag(tags []string) (string, bool) {
+	seen := map[string]bool{}
+	for _, t := range normalizeTags(tags) {
+		if seen[t] {
+			return t, true
+		}
+		seen[t] = true
+	}
+	return "", false
+}
@@ -0,0 +1,81 @@
+package forem
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateTag(t *testing.T) {
+	cases := map[string]struct {
+		tag       string
+		wantError string
+	}{
+		"lowercase":      {tag: "terraform"},
+		"uppercase":      {tag: "Terraform"},
+		"digits":         {tag: "web3"},
+		"unicode":        {tag: "café"},
+		"max length":     {tag: strings.Repeat("a", maxTagLength)},
+		"space":          {tag: "infra as code", wantError: "letters and digits"},
+		"hyphen":         {tag: "dev-ops", wantError: "letters and digits"},
+		"empty":          {tag: "", wantError: "letters and digits"},
+		"too long":       {tag: strings.Repeat("a", maxTagLength+1), wantError: "longer than 30 characters"},
+		"too long runes": {tag: strings.Repeat("é", maxTagLength+1), wantError: "longer than 30 characters"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, errs := validateTag(tc.tag, "tags.0")
+			if tc.wantError == "" {
+				if len(errs) > 0 {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tc.wantError) {
+				t.Fatalf("expected error containing `%s`, got %v", tc.wantError, errs)
+			}
+		})
+	}
+}
+
+func TestDuplicateTag(t *testing.T) {
+	if tag, ok := duplicateTag([]string{"go", "terraform"}); ok {
+		t.Errorf("expected no duplicate tag, got `%s`", tag)
+	}
+	if tag, ok := duplicateTag([]string{"go", "terraform", "Go"}); !ok || tag != "go" {
+		t.Errorf("expected duplicate tag `go`, got `%s`", tag)
+	}
+}
+
+func TestResourceListingDiff_tags(t *testing.T) {
+	r := resourceListing()
+	state := &terraform.InstanceState{ID: "42", Attributes: map[string]string{
+		"id":            "42",
+		"title":         "listing",
+		"body_markdown": "body",
+		"body_sha256":   markdownSHA256("body"),
+		"category":      "misc",
+		"tags.#":        "1",
+		"tags.0":        "terraform",
+		"tags_all.#":    "1",
+		"tags_all.0":    "terraform",
+	}}
+	config := map[string]interface{}{"title": "listing", "body_markdown": "body", "category": "misc", "tags": []interface{}{"Terraform"}}
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), &apiClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for k, attr := range diff.Attributes {
+		if strings.HasPrefix(k, "tags") && attr.Old != attr.New {
+			t.Errorf("expected tags differing in case only not to change, got %s: %+v", k, attr)
+		}
+	}
+
+	config["tags"] = []interface{}{"terraform", "Terraform"}
+	if _, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), &apiClient{}); err == nil || !strings.Contains(err.Error(), "duplicate tag `terraform`") {
+		t.Errorf("expected an error about the duplicate tag, got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package forem
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dev "github.com/karvounis/dev-client-go"
+)
+
+func TestRetryTransport_retriesReadsOnServerErrors(t *testing.T) {
+	var calls int32
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			writeDevError(w, http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"id":1,"title":"listing"}`)
+	}, 3)
+
+	listing, err := c.GetListingByID("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if listing.Title != "listing" {
+		t.Errorf("expected title `listing`, got `%s`", listing.Title)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_honorsRetryAfter(t *testing.T) {
+	var calls int32
+	var first time.Time
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			writeDevError(w, http.StatusTooManyRequests)
+			return
+		}
+		if elapsed := time.Since(first); elapsed < time.Second {
+			t.Errorf("retried after %s, before Retry-After elapsed", elapsed)
+		}
+		fmt.Fprint(w, `{"id":1,"title":"listing"}`)
+	}, 1)
+
+	if _, err := c.GetListingByID("1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_retriesCreateOnlyWhenRateLimited(t *testing.T) {
+	var calls int32
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			writeDevError(w, http.StatusTooManyRequests)
+		default:
+			writeDevError(w, http.StatusInternalServerError)
+		}
+	}, 3)
+
+	var lbc dev.ListingBodySchema
+	lbc.Listing.Title = "listing"
+	if _, err := c.CreateListing(lbc, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_givesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeDevError(w, http.StatusServiceUnavailable)
+	}, 2)
+
+	if _, err := c.GetListingByID("1"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_resendsBodyOnRetry(t *testing.T) {
+	var calls int32
+	var bodies []int64
+	c := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		bodies = append(bodies, r.ContentLength)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			writeDevError(w, http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"id":1,"title":"listing"}`)
+	}, 1)
+
+	var lbc dev.ListingBodySchema
+	lbc.Listing.Title = "listing"
+	if _, err := c.UpdateListing("1", lbc, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(bodies) != 2 || bodies[0] == 0 || bodies[0] != bodies[1] {
+		t.Errorf("expected the same body to be sent twice, got content lengths %v", bodies)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		value string
+		ok    bool
+		min   time.Duration
+		max   time.Duration
+	}{
+		"empty":   {value: "", ok: false},
+		"seconds": {value: "5", ok: true, min: 5 * time.Second, max: 5 * time.Second},
+		"date":    {value: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), ok: true, min: 58 * time.Second, max: time.Minute},
+		"invalid": {value: "soon", ok: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, ok := parseRetryAfter(tc.value)
+			if ok != tc.ok {
+				t.Fatalf("expected ok=%t, got %t", tc.ok, ok)
+			}
+			if ok && (d < tc.min || d > tc.max) {
+				t.Errorf("expected duration between %s and %s, got %s", tc.min, tc.max, d)
+			}
+		})
+	}
+}
+
+func TestNewHTTPTransport_tls(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	cases := map[string]struct {
+		opts      httpTransportOptions
+		wantError bool
+	}{
+		"untrusted certificate": {opts: httpTransportOptions{}, wantError: true},
+		"ca certificate":        {opts: httpTransportOptions{CACertPEM: caCert}},
+		"insecure skip verify":  {opts: httpTransportOptions{InsecureSkipVerify: true}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			transport, err := newHTTPTransport(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tc.wantError {
+				t.Errorf("expected error: %t, got %v", tc.wantError, err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPTransport_proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		fmt.Fprint(w, `{"id":1,"title":"listing"}`)
+	}))
+	defer proxy.Close()
+
+	transport, err := newHTTPTransport(httpTransportOptions{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://forem.example.com/api/listings/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if proxied != "http://forem.example.com/api/listings/1" {
+		t.Errorf("expected request to go through the proxy, got `%s`", proxied)
+	}
+}
+
+func TestNewHTTPTransport_invalidCertificates(t *testing.T) {
+	if _, err := newHTTPTransport(httpTransportOptions{CACertPEM: []byte("not a certificate")}); err == nil {
+		t.Error("expected error for an invalid CA certificate")
+	}
+	if _, err := newHTTPTransport(httpTransportOptions{ClientCertPEM: []byte("not a certificate")}); err == nil {
+		t.Error("expected error for an invalid client certificate")
+	}
+}
+
+func TestAPIClient_requestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	c, err := newAPIClient(apiClientOptions{Host: srv.URL, Token: "test", RequestTimeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetListingByID("1"); err == nil {
+		t.Error("expected request to time out")
+	}
+}
+
+func TestAPIClient_requestTimeoutPerAttempt(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			time.Sleep(100 * time.Millisecond)
+		case 2:
+			writeDevError(w, http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, `{"id": 1}`)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := newAPIClient(apiClientOptions{
+		Host:           srv.URL,
+		Token:          "test",
+		MaxRetries:     3,
+		RetryMaxWait:   time.Second,
+		RequestTimeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Client.Client.Transport.(*retryTransport).minWait = 60 * time.Millisecond
+
+	start := time.Now()
+	if _, err := c.GetListingByID("1"); err != nil {
+		t.Fatalf("expected the request to succeed after retries: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected the retries to take longer than the timeout, took %s", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
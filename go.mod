@@ -1,12 +1,13 @@
 module terraform-provider-forem
 
-go 1.17
+go 1.18
 
 require (
 	github.com/brianvoe/gofakeit/v6 v6.15.0
 	github.com/hashicorp/terraform-plugin-log v0.2.0
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.10.1
 	github.com/karvounis/dev-client-go v1.2.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (